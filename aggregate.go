@@ -0,0 +1,167 @@
+package emt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// AggregateError is the error type returned by the Resolve method of
+// the catchers in this package. It retains all of the constituent
+// errors, so that errors.Is and errors.As can find sentinels and
+// concrete types among them, and renders its message using the
+// formatting of the catcher that produced it.
+type AggregateError struct {
+	errs    []error
+	dropped int
+	render  func(error) string
+	format  Formatter
+}
+
+// Error returns the rendered form of all constituent errors, one
+// error per line. When the catcher that produced the error discarded
+// errors, the output ends with a line that reports how many.
+func (e *AggregateError) Error() string {
+	return joinErrors(e.errs, e.dropped, e.render, e.format)
+}
+
+// Format implements fmt.Formatter. The %v and %s verbs render the
+// same string as Error, %q renders it as a quoted string, and %+v
+// renders each constituent error with its own %+v format, which
+// includes stack traces and other details when the errors provide
+// them.
+func (e *AggregateError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, joinErrors(e.errs, e.dropped, renderExtended, e.format))
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// Dropped returns the number of errors that the catcher discarded
+// before the error was resolved.
+func (e *AggregateError) Dropped() int { return e.dropped }
+
+// Errors returns a copy of the constituent errors.
+func (e *AggregateError) Errors() []error {
+	out := make([]error, len(e.errs))
+	copy(out, e.errs)
+	return out
+}
+
+// Unwrap returns the constituent errors, and supports the multi-error
+// unwrapping in the standard library's errors package.
+func (e *AggregateError) Unwrap() []error { return e.Errors() }
+
+// Is returns true if any of the constituent errors matches the
+// target, as in errors.Is. This supports versions of the errors
+// package that do not unwrap multi-errors.
+func (e *AggregateError) Is(target error) bool {
+	for _, err := range e.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first constituent error that matches the target, as
+// in errors.As. This supports versions of the errors package that do
+// not unwrap multi-errors.
+func (e *AggregateError) As(target interface{}) bool {
+	for _, err := range e.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinErrorType is the type of the errors that errors.Join returns,
+// with Go 1.20 or later.
+var joinErrorType reflect.Type
+
+// aggregatedErrors returns the constituents of catchers, aggregate
+// errors and the errors from errors.Join. Other errors that hold
+// several errors, such as those from fmt.Errorf with several %w
+// verbs, may add context or have types of their own, and are not
+// aggregates.
+func aggregatedErrors(err error) ([]error, bool) {
+	switch e := err.(type) {
+	case *AggregateError:
+		return e.Errors(), true
+	case Catcher:
+		return e.Unwrap(), true
+	}
+
+	if joinErrorType != nil && reflect.TypeOf(err) == joinErrorType {
+		return err.(interface{ Unwrap() []error }).Unwrap(), true
+	}
+
+	return nil, false
+}
+
+// flattenErrors replaces catchers, aggregate errors and the errors
+// from errors.Join with their constituents, recursively. When there
+// are no such errors, flattenErrors returns the slice passed.
+func flattenErrors(errs []error) []error {
+	for idx, err := range errs {
+		if _, ok := aggregatedErrors(err); !ok {
+			continue
+		}
+
+		out := make([]error, idx, len(errs))
+		copy(out, errs[:idx])
+		for _, err := range errs[idx:] {
+			out = appendFlattened(out, err)
+		}
+
+		return out
+	}
+
+	return errs
+}
+
+func appendFlattened(out []error, err error) []error {
+	errs, ok := aggregatedErrors(err)
+	if !ok {
+		return append(out, err)
+	}
+
+	for _, err := range errs {
+		out = appendFlattened(out, err)
+	}
+
+	return out
+}
+
+// joinErrors renders each error and combines the messages with the
+// formatter, by default one error per line.
+func joinErrors(errs []error, dropped int, render func(error) string, format Formatter) string {
+	if len(errs) == 0 {
+		return ""
+	}
+
+	if render == nil {
+		render = renderBasic
+	}
+
+	if format == nil {
+		format = NewlineFormatter()
+	}
+
+	output := make([]string, len(errs))
+	for idx, err := range errs {
+		output[idx] = render(err)
+	}
+
+	return format.FormatErrors(output, dropped)
+}
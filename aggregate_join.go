@@ -0,0 +1,10 @@
+//go:build go1.20
+
+package emt
+
+import (
+	"errors"
+	"reflect"
+)
+
+func init() { joinErrorType = reflect.TypeOf(errors.Join(errors.New("join"))) }
@@ -0,0 +1,95 @@
+package emt
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestAggregateError(t *testing.T) {
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("ConcreteType", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("hello")
+
+				var agg *AggregateError
+				if !errors.As(catcher.Resolve(), &agg) {
+					t.Fatalf("resolved error is %T", catcher.Resolve())
+				}
+				if len(agg.Errors()) != 1 {
+					t.Fatalf("aggregate has %d errors", len(agg.Errors()))
+				}
+			})
+			t.Run("RendersAsString", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("hello")
+				catcher.Errorf("%d world", 42)
+
+				if err := catcher.Resolve(); err.Error() != catcher.String() {
+					t.Fatalf("resolved error %q does not match string %q", err.Error(), catcher.String())
+				}
+			})
+			t.Run("Is", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("hello")
+				catcher.Add(context.Canceled)
+
+				err := catcher.Resolve()
+				if !errors.Is(err, context.Canceled) {
+					t.Fatalf("%v should be canceled", err)
+				}
+				if errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("%v should not be a timeout", err)
+				}
+				if !err.(*AggregateError).Is(context.Canceled) {
+					t.Fatal("legacy Is method should find constituent")
+				}
+			})
+			t.Run("As", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("hello")
+				catcher.Add(&fs.PathError{Op: "open", Path: "/tmp/foo", Err: fs.ErrNotExist})
+
+				err := catcher.Resolve()
+
+				var perr *fs.PathError
+				if !errors.As(err, &perr) {
+					t.Fatalf("%v should contain a path error", err)
+				}
+				if perr.Path != "/tmp/foo" {
+					t.Fatalf("unexpected path error %v", perr)
+				}
+				if !errors.Is(err, fs.ErrNotExist) {
+					t.Fatal("should find errors wrapped by constituents")
+				}
+
+				perr = nil
+				if !err.(*AggregateError).As(&perr) || perr == nil {
+					t.Fatal("legacy As method should find constituent")
+				}
+			})
+			t.Run("Unwrap", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("one")
+				catcher.New("two")
+
+				errs := catcher.Resolve().(interface{ Unwrap() []error }).Unwrap()
+				if len(errs) != 2 {
+					t.Fatalf("unwrapped %d errors", len(errs))
+				}
+			})
+			t.Run("Snapshot", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("one")
+				err := catcher.Resolve()
+				catcher.New("two")
+
+				if n := len(err.(*AggregateError).Errors()); n != 1 {
+					t.Fatalf("resolved error should not change after resolution, has %d", n)
+				}
+			})
+		})
+	}
+}
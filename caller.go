@@ -0,0 +1,99 @@
+package emt
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Caller identifies the code that added an error to a catcher that
+// records callers, with the WithCallerAnnotation option.
+type Caller struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String renders the caller as "function (file:line)".
+func (c Caller) String() string { return fmt.Sprintf("%s (%s:%d)", c.Function, c.File, c.Line) }
+
+// findCaller returns the first frame of the stack outside of this
+// package and the runtime, which does not exist when this package
+// adds the error from its own goroutine, as Group does.
+func findCaller(pcs []uintptr) (Caller, bool) {
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.PC != 0 && !isInternalFrame(frame) && !strings.HasPrefix(frame.Function, "runtime.") {
+			return Caller{Function: frame.Function, File: frame.File, Line: frame.Line}, true
+		}
+		if !more {
+			return Caller{}, false
+		}
+	}
+}
+
+// callerError annotates an error with the code that added it to a
+// catcher.
+type callerError struct {
+	err    error
+	caller Caller
+}
+
+func (e *callerError) Cause() error  { return e.err }
+func (e *callerError) Unwrap() error { return e.err }
+func (e *callerError) Error() string { return e.err.Error() }
+
+// Caller returns the code that added the error to the catcher.
+func (e *callerError) Caller() Caller { return e.caller }
+
+func (e *callerError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "%+v\n\tadded by %s", e.err, e.caller)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = fmt.Fprintf(s, "%s", e.err)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.err.Error())
+	}
+}
+
+// ErrorCallerFinder finds the code that added the error to a catcher
+// that records callers, with the WithCallerAnnotation option,
+// unwrapping the error as needed.
+func ErrorCallerFinder(err error) (Caller, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *callerError:
+			return e.caller, true
+		case interface{ Cause() error }:
+			err = e.Cause()
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return Caller{}, false
+		}
+	}
+
+	return Caller{}, false
+}
+
+// withCaller annotates the error with the first caller in the stack
+// outside of this package, unless the error has a caller already,
+// for instance when it was added to another catcher.
+func withCaller(err error, pcs []uintptr) error {
+	if _, ok := ErrorCallerFinder(err); ok {
+		return err
+	}
+
+	caller, ok := findCaller(pcs)
+	if !ok {
+		return err
+	}
+
+	return annotateInner(err, func(err error) error { return &callerError{err: err, caller: caller} })
+}
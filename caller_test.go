@@ -0,0 +1,174 @@
+package emt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCallerAnnotation(t *testing.T) {
+	assertCaller := func(t *testing.T, err error) Caller {
+		t.Helper()
+		caller, ok := ErrorCallerFinder(err)
+		if !ok {
+			t.Fatalf("should find caller for %v", err)
+		}
+		if !strings.Contains(caller.Function, "TestCallerAnnotation") {
+			t.Fatalf("caller should be the test, not %s", caller.Function)
+		}
+		if !strings.HasSuffix(caller.File, "caller_test.go") {
+			t.Fatalf("caller should be in the test, not %s", caller.File)
+		}
+		return caller
+	}
+
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("Disabled", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("hello")
+				if _, ok := ErrorCallerFinder(catcher.Errors()[0]); ok {
+					t.Fatal("catchers should not record callers by default")
+				}
+			})
+			t.Run("Line", func(t *testing.T) {
+				catcher := fix.Factory(WithCallerAnnotation())
+				_, _, line, _ := runtime.Caller(0)
+				catcher.Add(errors.New("hello"))
+
+				if caller := assertCaller(t, catcher.Errors()[0]); caller.Line != line+1 {
+					t.Fatalf("caller should be line %d, not %d", line+1, caller.Line)
+				}
+			})
+			t.Run("SkipsWrappers", func(t *testing.T) {
+				ctx := context.Background()
+				fn := func() error { return errors.New("check") }
+
+				for name, add := range map[string]func(Catcher){
+					"AddWhen":      func(c Catcher) { c.AddWhen(true, errors.New("one")) },
+					"AddLevel":     func(c Catcher) { c.AddLevel(LevelWarning, errors.New("one")) },
+					"Extend":       func(c Catcher) { c.Extend([]error{errors.New("one")}) },
+					"ExtendWhen":   func(c Catcher) { c.ExtendWhen(true, []error{errors.New("one")}) },
+					"New":          func(c Catcher) { c.New("one") },
+					"NewWhen":      func(c Catcher) { c.NewWhen(true, "one") },
+					"Errorf":       func(c Catcher) { c.Errorf("one %d", 1) },
+					"ErrorfNoArgs": func(c Catcher) { c.Errorf("one") },
+					"ErrorfWhen":   func(c Catcher) { c.ErrorfWhen(true, "one %d", 1) },
+					"Check":        func(c Catcher) { c.Check(fn) },
+					"CheckWhen":    func(c Catcher) { c.CheckWhen(true, fn) },
+					"CheckExtend":  func(c Catcher) { c.CheckExtend([]CheckFunction{fn}) },
+					"CheckCtx":     func(c Catcher) { c.CheckCtx(ctx, func(context.Context) error { return fn() }) },
+					"CheckCtxExtend": func(c Catcher) {
+						c.CheckCtxExtend(ctx, []ContextCheckFunction{func(context.Context) error { return fn() }})
+					},
+				} {
+					t.Run(name, func(t *testing.T) {
+						catcher := fix.Factory(WithCallerAnnotation())
+						add(catcher)
+						if catcher.Len() != 1 {
+							t.Fatalf("catcher has %d errors", catcher.Len())
+						}
+						assertCaller(t, catcher.Errors()[0])
+					})
+				}
+			})
+			t.Run("KeepsOriginalCaller", func(t *testing.T) {
+				inner := NewBasicCatcher(WithCallerAnnotation())
+				_, _, line, _ := runtime.Caller(0)
+				inner.New("hello")
+
+				catcher := fix.Factory(WithCallerAnnotation())
+				catcher.Add(inner)
+
+				if caller := assertCaller(t, catcher.Errors()[0]); caller.Line != line+1 {
+					t.Fatalf("caller should be the original caller at line %d, not %d", line+1, caller.Line)
+				}
+			})
+			t.Run("WithStackCapture", func(t *testing.T) {
+				catcher := fix.Factory(WithCallerAnnotation(), WithStackCapture())
+				catcher.New("hello")
+
+				err := catcher.Errors()[0]
+				assertCaller(t, err)
+				if _, ok := ErrorStackFinder(err); !ok {
+					t.Fatal("should find stack")
+				}
+			})
+		})
+	}
+	t.Run("Format", func(t *testing.T) {
+		catcher := NewExtendedCatcher(WithCallerAnnotation())
+		catcher.New("hello")
+
+		caller := assertCaller(t, catcher.Errors()[0])
+		if !strings.Contains(catcher.String(), "hello\n\tadded by "+caller.String()) {
+			t.Fatalf("extended catcher should render the caller: %s", catcher)
+		}
+		if !strings.Contains(fmt.Sprintf("%+v", catcher.Resolve()), caller.String()) {
+			t.Fatal("the extended format should render the caller")
+		}
+
+		basic := NewBasicCatcher(WithCallerAnnotation())
+		basic.New("hello")
+		if basic.String() != "hello" {
+			t.Fatalf("basic catcher should not render the caller: %s", basic)
+		}
+
+		err := basic.Errors()[0]
+		for verb, expected := range map[string]string{"%s": "hello", "%v": "hello", "%q": `"hello"`} {
+			if out := fmt.Sprintf(verb, err); out != expected {
+				t.Errorf("%s rendered %q, expected %q", verb, out, expected)
+			}
+		}
+	})
+	t.Run("ExtendedTimestampFormat", func(t *testing.T) {
+		catcher := NewExtendedTimestampCatcher(WithCallerAnnotation())
+		catcher.New("hello")
+
+		if !strings.Contains(fmt.Sprintf("%+v", catcher.Errors()[0]), "added by") {
+			t.Fatal("extended timestamp errors should render the caller")
+		}
+	})
+	t.Run("JSON", func(t *testing.T) {
+		catcher := NewTimestampCatcher(WithCallerAnnotation())
+		catcher.New("hello")
+		caller := assertCaller(t, catcher.Errors()[0])
+
+		data, err := json.Marshal(catcher.Resolve())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out []errorJSON
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out[0].Caller == nil || *out[0].Caller != caller {
+			t.Fatalf("json should include the caller: %s", data)
+		}
+		if out[0].Type != "*errors.errorString" || len(out[0].Causes) != 0 {
+			t.Fatalf("json should not include the annotation: %s", data)
+		}
+
+		decoded := &AggregateError{}
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatal(err)
+		}
+		if found, ok := ErrorCallerFinder(decoded.Errors()[0]); !ok || found != caller {
+			t.Fatalf("decoding should retain the caller: %v", found)
+		}
+		if again, err := json.Marshal(decoded); err != nil || string(again) != string(data) {
+			t.Fatalf("round trip changed json: %s != %s", again, data)
+		}
+	})
+	t.Run("String", func(t *testing.T) {
+		caller := Caller{Function: "main.run", File: "/src/main.go", Line: 12}
+		if caller.String() != "main.run (/src/main.go:12)" {
+			t.Fatalf("unexpected rendering %q", caller)
+		}
+	})
+}
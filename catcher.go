@@ -5,9 +5,9 @@
 package emt
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"sync"
 )
 
@@ -15,6 +15,10 @@ import (
 // error.
 type CheckFunction func() error
 
+// ContextCheckFunction are functions which take a context, and
+// should return when the context is canceled, and return an error.
+type ContextCheckFunction func(context.Context) error
+
 // Catcher is an interface for an error collector for use when
 // implementing continue-on-error semantics in concurrent
 // operations. The different catcher implementations provided by this
@@ -42,15 +46,63 @@ type Catcher interface {
 	CheckExtend([]CheckFunction)
 	CheckWhen(bool, CheckFunction)
 
+	// The CheckCtx methods pass the context to the check
+	// functions. If the context is done, the catcher does not run
+	// the check and collects the context's error instead;
+	// CheckCtxExtend collects the context's error once and does
+	// not run the remaining checks. See WithCheckTimeout to limit
+	// the duration of each check.
+	CheckCtx(context.Context, ContextCheckFunction)
+	CheckCtxExtend(context.Context, []ContextCheckFunction)
+	CheckCtxWhen(context.Context, bool, ContextCheckFunction)
+
 	Resolve() error
 	HasErrors() bool
 
+	// AddLevel adds the error annotated with the level, as
+	// WrapErrorLevel. HasErrorsAtLeast and ResolveAtLeast consider
+	// only the errors with a level at or above the threshold;
+	// errors added without a level have LevelError.
+	AddLevel(Level, error)
+	HasErrorsAtLeast(Level) bool
+	ResolveAtLeast(Level) error
+
+	// Reset removes all errors from the catcher and clears its
+	// counters. Drain and Flush return the contents of the catcher,
+	// as Resolve and Errors respectively, and reset the catcher in
+	// the same operation, so that no errors added concurrently are
+	// lost between reading and clearing the catcher.
+	Reset()
+	Drain() error
+	Flush() []error
+
 	Len() int
 	Errors() []error
 
+	// Dropped returns the number of errors that a catcher has
+	// discarded to stay within its size, or, for time window
+	// catchers, because they expired, and Total returns the number
+	// of errors the catcher has ingested, including the discarded
+	// errors. For catchers without a size or a window, Dropped is
+	// always zero and Total is the same as Len. The rendered
+	// errors only note the errors discarded to stay within the
+	// size, as expired errors are not missing from the output.
+	Dropped() int
+	Total() int
+
 	// String returns a string that concatenates the values
-	// returned by `.Error()` on all of the constituent errors.
+	// returned by `.Error()` on all of the constituent errors. If
+	// the catcher has dropped errors, the string ends with a line
+	// that reports how many.
 	String() string
+
+	// Error returns the same string as String, and is safe to call
+	// on a nil catcher. Unwrap returns the errors in the catcher,
+	// as Errors, so that errors.Is and errors.As can find errors
+	// in a catcher, and so that Add and Extend can flatten
+	// catchers.
+	Error() string
+	Unwrap() []error
 }
 
 // multiCatcher provides an interface to collect and coalesse error
@@ -58,10 +110,11 @@ type Catcher interface {
 // implement a kind of "continue on error"-style operations. The
 // methods on MultiCatatcher are thread-safe.
 type baseCatcher struct {
-	errs    []error
-	maxSize int
+	errs    errorRing
 	mutex   sync.RWMutex
-	fmt.Stringer
+	render  func(error) string
+	observe func(error)
+	opts    catcherOptions
 }
 
 // NewCatcher returns a Catcher instance that you can use to capture
@@ -70,61 +123,75 @@ type baseCatcher struct {
 //
 // DEPRECATED: use one of the other catcher implementations. See the
 // documentation for the Catcher interface for most implementations.
-func NewCatcher() Catcher { return NewExtendedCatcher() }
+func NewCatcher(opts ...CatcherOption) Catcher { return NewExtendedCatcher(opts...) }
 
 // NewBasicCatcher collects error messages and formats them using a
 // new-line separated string of the output of error.Error()
-func NewBasicCatcher() Catcher { return makeBasicCatcher(&baseCatcher{}) }
+func NewBasicCatcher(opts ...CatcherOption) Catcher { return MakeBasicCatcher(0, opts...) }
 
 // NewSimpleCatcher collects error messages and formats them using a
 // new-line separated string of the string format of the error message
 // (e.g. %s).
-func NewSimpleCatcher() Catcher { return makeSimpleCatcher(&baseCatcher{}) }
+func NewSimpleCatcher(opts ...CatcherOption) Catcher { return MakeSimpleCatcher(0, opts...) }
 
 // NewExtendedCatcher collects error messages and formats them using a
 // new-line separated string of the extended string format of the
 // error message (e.g. %+v).
-func NewExtendedCatcher() Catcher { return makeExtCatcher(&baseCatcher{}) }
+func NewExtendedCatcher(opts ...CatcherOption) Catcher { return MakeExtendedCatcher(0, opts...) }
 
 // MakeBasicCatcher collects error messages and formats them using a
 // new-line separated string of the output of error.Error(). If the
 // size greater than 0 the catcher will never collect more than the
 // specified number of errors, discarding earlier messages when adding
 // new messages.
-func MakeBasicCatcher(size int) Catcher { return makeBasicCatcher(&baseCatcher{maxSize: size}) }
+func MakeBasicCatcher(size int, opts ...CatcherOption) Catcher {
+	return newBaseCatcher(size, renderBasic, opts)
+}
 
 // MakeSimpleCatcher collects error messages and formats them using a
 // new-line separated string of the string format of the error message
 // (e.g. %s). If the size greater than 0 the catcher will never
 // collect more than the specified number of errors, discarding
 // earlier messages when adding new messages.
-func MakeSimpleCatcher(size int) Catcher { return makeSimpleCatcher(&baseCatcher{maxSize: size}) }
+func MakeSimpleCatcher(size int, opts ...CatcherOption) Catcher {
+	return newBaseCatcher(size, renderSimple, opts)
+}
 
 // MakeExtendedCatcher collects error messages and formats them using
 // a new-line separated string of the extended string format of the
 // error message (e.g. %+v). If the size greater than 0 the catcher
 // will never collect more than the specified number of errors,
 // discarding earlier messages when adding new messages.
-func MakeExtendedCatcher(size int) Catcher { return makeExtCatcher(&baseCatcher{maxSize: size}) }
+func MakeExtendedCatcher(size int, opts ...CatcherOption) Catcher {
+	return newBaseCatcher(size, renderExtended, opts)
+}
 
 // Add takes an error object and, if it's non-nil, adds it to the
-// internal collection of errors.
+// internal collection of errors. When the error is a catcher, an
+// aggregate error or an error from errors.Join, Add collects each of
+// its errors, rather than the error itself. Add collects other errors
+// that hold several errors, such as those from fmt.Errorf with several
+// %w verbs, as one error.
 func (c *baseCatcher) Add(err error) {
 	if err == nil {
 		return
+	} else if errs, ok := aggregatedErrors(err); ok {
+		c.Extend(errs)
+		return
 	}
 
+	err = c.opts.annotate(err, c.opts.stack())
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	c.safeAdd(err)
 }
 
 func (c *baseCatcher) safeAdd(err error) {
-	if c.maxSize <= 0 || c.maxSize > len(c.errs) {
-		c.errs = append(c.errs, err)
-	} else {
-		c.errs = c.errs[1:]
-		c.errs = append(c.errs, err)
+	c.errs.push(err)
+
+	if c.observe != nil {
+		c.observe(err)
 	}
 }
 
@@ -133,7 +200,7 @@ func (c *baseCatcher) Len() int {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	return len(c.errs)
+	return c.errs.len()
 }
 
 // Cap returns the capcity of the underlying storage in the collector.
@@ -141,7 +208,25 @@ func (c *baseCatcher) Cap() int {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	return cap(c.errs)
+	return c.errs.cap()
+}
+
+// Dropped returns the number of errors discarded to keep the
+// collector within its size.
+func (c *baseCatcher) Dropped() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.errs.dropped
+}
+
+// Total returns the number of errors ingested by the collector,
+// including those that it discarded.
+func (c *baseCatcher) Total() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.errs.total()
 }
 
 // HasErrors returns true if the collector has ingested errors, and
@@ -150,15 +235,33 @@ func (c *baseCatcher) HasErrors() bool {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	return len(c.errs) > 0
+	return c.errs.len() > 0
 }
 
-// Extend adds all non-nil errors, passed as arguments to the catcher.
+// AddLevel adds the error, annotated with the level, to the
+// collector.
+func (c *baseCatcher) AddLevel(level Level, err error) { c.Add(WrapErrorLevel(level, err)) }
+
+// HasErrorsAtLeast returns true if the collector has errors with a
+// level at or above the threshold.
+func (c *baseCatcher) HasErrorsAtLeast(threshold Level) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return hasLevel(c.errs.slice(), threshold)
+}
+
+// Extend adds all non-nil errors, passed as arguments to the
+// catcher, and flattens catchers, aggregate errors and the errors
+// from errors.Join, as Add.
 func (c *baseCatcher) Extend(errs []error) {
 	if len(errs) == 0 {
 		return
 	}
 
+	errs = flattenErrors(errs)
+	pcs := c.opts.stack()
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -167,7 +270,7 @@ func (c *baseCatcher) Extend(errs []error) {
 			continue
 		}
 
-		c.safeAdd(err)
+		c.safeAdd(c.opts.annotate(err, pcs))
 	}
 }
 
@@ -220,19 +323,42 @@ func (c *baseCatcher) NewWhen(cond bool, e string) {
 	c.New(e)
 }
 
-func (c *baseCatcher) Check(fn CheckFunction) { c.Add(fn()) }
+func (c *baseCatcher) Check(fn CheckFunction) { c.Add(c.opts.check(fn)) }
 
 func (c *baseCatcher) CheckWhen(cond bool, fn CheckFunction) {
 	if !cond {
 		return
 	}
 
-	c.Add(fn())
+	c.Add(c.opts.check(fn))
 }
 
 func (c *baseCatcher) CheckExtend(fns []CheckFunction) {
 	for _, fn := range fns {
-		c.Add(fn())
+		c.Add(c.opts.check(fn))
+	}
+}
+
+func (c *baseCatcher) CheckCtx(ctx context.Context, fn ContextCheckFunction) {
+	c.Add(c.opts.checkCtx(ctx, fn))
+}
+
+func (c *baseCatcher) CheckCtxWhen(ctx context.Context, cond bool, fn ContextCheckFunction) {
+	if !cond {
+		return
+	}
+
+	c.Add(c.opts.checkCtx(ctx, fn))
+}
+
+func (c *baseCatcher) CheckCtxExtend(ctx context.Context, fns []ContextCheckFunction) {
+	for _, fn := range fns {
+		if err := ctx.Err(); err != nil {
+			c.Add(err)
+			return
+		}
+
+		c.Add(c.opts.checkCtx(ctx, fn))
 	}
 }
 
@@ -240,75 +366,124 @@ func (c *baseCatcher) Errors() []error {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	out := make([]error, len(c.errs))
+	return c.errs.slice()
+}
+
+// String returns the rendered form of all collected errors, one
+// error per line, followed by a summary of the errors discarded.
+func (c *baseCatcher) String() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return joinErrors(c.errs.slice(), c.errs.dropped, c.render, c.opts.formatter)
+}
 
-	copy(out, c.errs)
+// Error returns the same string as String. A nil catcher renders as
+// the empty string.
+func (c *baseCatcher) Error() string {
+	if c == nil {
+		return ""
+	}
 
-	return out
+	return c.String()
 }
 
-// Resolve returns a final error object for the Catcher. If there are
-// no errors, it returns nil, and returns an error object with the
-// string form of all error objects in the collector.
-func (c *baseCatcher) Resolve() error {
-	if !c.HasErrors() {
+// Unwrap returns the errors in the collector, as Errors.
+func (c *baseCatcher) Unwrap() []error {
+	if c == nil {
 		return nil
 	}
 
-	return errors.New(c.String())
+	return c.Errors()
 }
 
-////////////////////////////////////////////////////////////////////////
-//
-// separate implementations of grip.Catcher with different string formatting options.
-
-type basicCatcherConstructor func(*baseCatcher)
+// Resolve returns a final error object for the Catcher. If there are
+// no errors, it returns nil, and otherwise returns an *AggregateError
+// that holds all error objects in the collector and renders them in
+// the same form as String.
+func (c *baseCatcher) Resolve() error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 
-func makeExtCatcher(bc *baseCatcher) Catcher    { c := &extendedCatcher{bc}; bc.Stringer = c; return bc }
-func makeSimpleCatcher(bc *baseCatcher) Catcher { c := &simpleCatcher{bc}; bc.Stringer = c; return bc }
-func makeBasicCatcher(bc *baseCatcher) Catcher  { c := &basicCatcher{bc}; bc.Stringer = c; return bc }
+	if c.errs.len() == 0 {
+		return nil
+	}
 
-type extendedCatcher struct{ *baseCatcher }
+	return &AggregateError{errs: c.errs.slice(), dropped: c.errs.dropped, render: c.render, format: c.opts.formatter}
+}
 
-func (c *extendedCatcher) String() string {
+// ResolveAtLeast returns an error, as Resolve, that holds only the
+// errors with a level at or above the threshold, or nil if there are
+// no such errors.
+func (c *baseCatcher) ResolveAtLeast(threshold Level) error {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	output := make([]string, len(c.errs))
-
-	for idx, err := range c.errs {
-		output[idx] = fmt.Sprintf("%+v", err)
+	errs := filterLevel(c.errs.slice(), threshold)
+	if len(errs) == 0 {
+		return nil
 	}
 
-	return strings.Join(output, "\n")
+	return &AggregateError{errs: errs, render: c.render, format: c.opts.formatter}
 }
 
-type simpleCatcher struct{ *baseCatcher }
+// Reset removes all errors from the collector.
+func (c *baseCatcher) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
-func (c *simpleCatcher) String() string {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	c.errs.reset()
+}
 
-	output := make([]string, len(c.errs))
+// Drain returns the resolved error for the collector, as Resolve,
+// and removes all errors from the collector.
+func (c *baseCatcher) Drain() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
-	for idx, err := range c.errs {
-		output[idx] = fmt.Sprintf("%s", err)
+	if c.errs.len() == 0 {
+		return nil
 	}
 
-	return strings.Join(output, "\n")
+	defer c.errs.reset()
+
+	return &AggregateError{errs: c.errs.slice(), dropped: c.errs.dropped, render: c.render, format: c.opts.formatter}
 }
 
-type basicCatcher struct{ *baseCatcher }
+// Flush returns the errors in the collector, as Errors, and removes
+// all errors from the collector.
+func (c *baseCatcher) Flush() []error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
-func (c *basicCatcher) String() string {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	defer c.errs.reset()
 
-	output := make([]string, len(c.errs))
+	return c.errs.slice()
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// separate implementations of grip.Catcher with different string formatting options.
 
-	for idx, err := range c.errs {
-		output[idx] = err.Error()
+func newBaseCatcher(size int, render func(error) string, opts []CatcherOption) *baseCatcher {
+	return &baseCatcher{
+		errs:   makeErrorRing(size),
+		render: render,
+		opts:   makeCatcherOptions(opts),
 	}
+}
 
-	return strings.Join(output, "\n")
+// emptyCopy returns a catcher with the same size, formatting and
+// options, but without errors. The copy does not share the observer,
+// so errors added to the copy are not logged again.
+func (c *baseCatcher) emptyCopy() Catcher {
+	return &baseCatcher{
+		errs:   makeErrorRing(c.errs.limit),
+		render: c.render,
+		opts:   c.opts,
+	}
 }
+
+func renderExtended(err error) string { return fmt.Sprintf("%+v", err) }
+func renderSimple(err error) string   { return fmt.Sprintf("%s", err) }
+func renderBasic(err error) string    { return err.Error() }
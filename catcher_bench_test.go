@@ -0,0 +1,62 @@
+package emt
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func BenchmarkCatcherAdd(b *testing.B) {
+	err := errors.New("benchmark")
+	fixtures := []struct {
+		Name    string
+		Factory func(int, ...CatcherOption) Catcher
+	}{
+		{Name: "Basic", Factory: MakeBasicCatcher},
+		{Name: "Extended", Factory: MakeExtendedCatcher},
+		{Name: "Timestamp", Factory: MakeTimestampCatcher},
+	}
+
+	for _, fix := range fixtures {
+		b.Run(fix.Name, func(b *testing.B) {
+			for _, size := range []int{10, 1000, 100000} {
+				b.Run(fmt.Sprintf("Fixed/%d", size), func(b *testing.B) {
+					catcher := fix.Factory(size)
+					for i := 0; i < size; i++ {
+						catcher.Add(err)
+					}
+					b.ReportAllocs()
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						catcher.Add(err)
+					}
+				})
+			}
+			b.Run("Unbounded", func(b *testing.B) {
+				catcher := fix.Factory(0)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					catcher.Add(err)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkCatcherErrors(b *testing.B) {
+	err := errors.New("benchmark")
+	for _, size := range []int{10, 1000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			catcher := MakeBasicCatcher(size)
+			for i := 0; i < 2*size; i++ {
+				catcher.Add(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = catcher.Errors()
+			}
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package emt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// duplicateError is the error that the deduplicating catcher reports
+// for each group of errors. It wraps the first error in the group,
+// annotated with the time the catcher first saw it, so that
+// ErrorTimeFinder reports the first-seen time.
+type duplicateError struct {
+	err   *timestampError
+	count int
+	last  time.Time
+}
+
+// Count returns the number of errors collected in the group.
+func (e *duplicateError) Count() int { return e.count }
+
+// FirstSeen returns the time that the catcher collected the first
+// error in the group.
+func (e *duplicateError) FirstSeen() time.Time { return e.err.time }
+
+// LastSeen returns the time that the catcher collected the most
+// recent error in the group.
+func (e *duplicateError) LastSeen() time.Time { return e.last }
+
+func (e *duplicateError) Cause() error  { return e.err }
+func (e *duplicateError) Unwrap() error { return e.err }
+func (e *duplicateError) Error() string {
+	if e.count <= 1 {
+		return fmt.Sprintf("%s (%s)", e.err.String(), e.seen(e.err.time))
+	}
+
+	return fmt.Sprintf("%s (x%d, first %s, last %s)",
+		e.err.String(), e.count, e.seen(e.err.time), e.seen(e.last))
+}
+
+// seen renders a time of the group with the timestamp layout and
+// location of the catcher, if any, and otherwise as a time of day.
+func (e *duplicateError) seen(ts time.Time) string { return e.err.formatTime(ts, "15:04:05") }
+
+////////////////////////////////////////////////////////////////////////
+//
+// an implementation that collapses repeated errors
+
+type dedupCatcher struct {
+	mu     sync.RWMutex
+	key    func(error) string
+	index  map[string]*duplicateError
+	groups []*duplicateError
+	total  int
+	opts   catcherOptions
+}
+
+// NewDedupCatcher produces a Catcher instance that groups errors with
+// the same message, and reports each group once, with the number of
+// times the error was collected and the times that the catcher first
+// and last saw it. Len reports the number of groups, while Total
+// reports the number of errors collected.
+func NewDedupCatcher(opts ...CatcherOption) Catcher { return MakeDedupCatcher(nil, opts...) }
+
+// MakeDedupCatcher constructs a deduplicating Catcher, like
+// NewDedupCatcher, that groups errors by the key that the function
+// returns for each error. If the key function is nil, the catcher
+// groups errors by their message.
+func MakeDedupCatcher(key func(error) string, opts ...CatcherOption) Catcher {
+	if key == nil {
+		key = dedupKeyMessage
+	}
+
+	return &dedupCatcher{
+		key:   key,
+		index: map[string]*duplicateError{},
+		opts:  makeCatcherOptions(opts),
+	}
+}
+
+// dedupKeyMessage groups errors by the message of the error, without
+// any timestamp annotation.
+func dedupKeyMessage(err error) string {
+	if tserr, ok := err.(*timestampError); ok {
+		return tserr.String()
+	}
+
+	return err.Error()
+}
+
+func (c *dedupCatcher) emptyCopy() Catcher {
+	return &dedupCatcher{
+		key:   c.key,
+		index: map[string]*duplicateError{},
+		opts:  c.opts,
+	}
+}
+
+func (c *dedupCatcher) Add(err error) {
+	if err == nil {
+		return
+	} else if errs, ok := aggregatedErrors(err); ok {
+		c.Extend(errs)
+		return
+	}
+
+	err = c.opts.annotate(err, c.opts.stack())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.safeAdd(err)
+}
+
+func (c *dedupCatcher) safeAdd(err error) {
+	if dup, ok := err.(*duplicateError); ok {
+		c.safeMerge(dup)
+		return
+	}
+
+	ts, ok := ErrorTimeFinder(err)
+	if !ok {
+		ts = c.opts.now()
+	} else if c.opts.utc {
+		ts = ts.UTC()
+	}
+
+	c.total++
+
+	key := c.key(err)
+	if group, ok := c.index[key]; ok {
+		group.count++
+		if ts.After(group.last) {
+			group.last = ts
+		}
+		return
+	}
+
+	tserr, ok := err.(*timestampError)
+	if ok {
+		tserr = c.opts.annotateTime(tserr)
+	} else {
+		tserr = &timestampError{err: err, time: ts, layout: c.opts.layout, loc: c.opts.loc}
+	}
+
+	group := &duplicateError{err: tserr, count: 1, last: ts}
+	c.index[key] = group
+	c.groups = append(c.groups, group)
+}
+
+// safeMerge adds a group reported by a deduplicating catcher, keeping
+// its count, and combines it with the existing group that has the
+// same key, if any. The key function receives the first error in the
+// group.
+func (c *dedupCatcher) safeMerge(dup *duplicateError) {
+	c.total += dup.count
+
+	first, last := c.opts.annotateTime(dup.err), dup.last
+	if c.opts.utc {
+		last = last.UTC()
+	}
+
+	key := c.key(dup.err.err)
+	if group, ok := c.index[key]; ok {
+		group.count += dup.count
+		if first.time.Before(group.err.time) {
+			group.err = first
+		}
+		if last.After(group.last) {
+			group.last = last
+		}
+		return
+	}
+
+	group := &duplicateError{err: first, count: dup.count, last: last}
+	c.index[key] = group
+	c.groups = append(c.groups, group)
+}
+
+func (c *dedupCatcher) Extend(errs []error) {
+	if len(errs) == 0 {
+		return
+	}
+
+	errs = flattenErrors(errs)
+	pcs := c.opts.stack()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		c.safeAdd(c.opts.annotate(err, pcs))
+	}
+}
+
+func (c *dedupCatcher) AddLevel(level Level, err error) { c.Add(WrapErrorLevel(level, err)) }
+
+func (c *dedupCatcher) AddWhen(cond bool, err error) {
+	if !cond {
+		return
+	}
+
+	c.Add(err)
+}
+
+func (c *dedupCatcher) ExtendWhen(cond bool, errs []error) {
+	if !cond {
+		return
+	}
+
+	c.Extend(errs)
+}
+
+func (c *dedupCatcher) New(e string) {
+	if e == "" {
+		return
+	}
+
+	c.Add(errors.New(e))
+}
+
+func (c *dedupCatcher) NewWhen(cond bool, e string) {
+	if !cond {
+		return
+	}
+
+	c.New(e)
+}
+
+func (c *dedupCatcher) Errorf(f string, args ...interface{}) {
+	if f == "" {
+		return
+	} else if len(args) == 0 {
+		c.New(f)
+		return
+	}
+
+	c.Add(fmt.Errorf(f, args...))
+}
+
+func (c *dedupCatcher) ErrorfWhen(cond bool, f string, args ...interface{}) {
+	if !cond {
+		return
+	}
+
+	c.Errorf(f, args...)
+}
+
+func (c *dedupCatcher) Check(fn CheckFunction) {
+	c.Add(c.opts.check(fn))
+}
+
+func (c *dedupCatcher) CheckWhen(cond bool, fn CheckFunction) {
+	if !cond {
+		return
+	}
+
+	c.Add(c.opts.check(fn))
+}
+
+func (c *dedupCatcher) CheckExtend(fns []CheckFunction) {
+	for _, fn := range fns {
+		c.Add(c.opts.check(fn))
+	}
+}
+
+func (c *dedupCatcher) CheckCtx(ctx context.Context, fn ContextCheckFunction) {
+	c.Add(c.opts.checkCtx(ctx, fn))
+}
+
+func (c *dedupCatcher) CheckCtxWhen(ctx context.Context, cond bool, fn ContextCheckFunction) {
+	if !cond {
+		return
+	}
+
+	c.Add(c.opts.checkCtx(ctx, fn))
+}
+
+func (c *dedupCatcher) CheckCtxExtend(ctx context.Context, fns []ContextCheckFunction) {
+	for _, fn := range fns {
+		if err := ctx.Err(); err != nil {
+			c.Add(err)
+			return
+		}
+
+		c.Add(c.opts.checkCtx(ctx, fn))
+	}
+}
+
+func (c *dedupCatcher) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.groups)
+}
+
+func (c *dedupCatcher) Cap() int { return 0 }
+
+func (c *dedupCatcher) Dropped() int { return 0 }
+
+func (c *dedupCatcher) Total() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.total
+}
+
+func (c *dedupCatcher) HasErrors() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.groups) > 0
+}
+
+// safeErrors returns copies of the groups so that the errors remain
+// stable when the catcher collects more errors.
+func (c *dedupCatcher) safeErrors() []error {
+	out := make([]error, len(c.groups))
+	for idx, group := range c.groups {
+		cp := *group
+		out[idx] = &cp
+	}
+
+	return out
+}
+
+func (c *dedupCatcher) HasErrorsAtLeast(threshold Level) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return hasLevel(c.safeErrors(), threshold)
+}
+
+func (c *dedupCatcher) Errors() []error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.safeErrors()
+}
+
+func (c *dedupCatcher) String() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return joinErrors(c.safeErrors(), 0, renderBasic, c.opts.formatter)
+}
+
+func (c *dedupCatcher) Error() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.String()
+}
+
+func (c *dedupCatcher) Unwrap() []error {
+	if c == nil {
+		return nil
+	}
+
+	return c.Errors()
+}
+
+func (c *dedupCatcher) Resolve() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.groups) == 0 {
+		return nil
+	}
+
+	return &AggregateError{errs: c.safeErrors(), render: renderBasic, format: c.opts.formatter}
+}
+
+func (c *dedupCatcher) safeReset() {
+	c.index = map[string]*duplicateError{}
+	c.groups = nil
+	c.total = 0
+}
+
+func (c *dedupCatcher) ResolveAtLeast(threshold Level) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	errs := filterLevel(c.safeErrors(), threshold)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &AggregateError{errs: errs, render: renderBasic, format: c.opts.formatter}
+}
+
+func (c *dedupCatcher) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.safeReset()
+}
+
+func (c *dedupCatcher) Drain() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.groups) == 0 {
+		return nil
+	}
+
+	defer c.safeReset()
+
+	return &AggregateError{errs: c.safeErrors(), render: renderBasic, format: c.opts.formatter}
+}
+
+func (c *dedupCatcher) Flush() []error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	defer c.safeReset()
+
+	return c.safeErrors()
+}
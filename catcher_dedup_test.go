@@ -0,0 +1,205 @@
+package emt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedupCatcher(t *testing.T) {
+	t.Run("InitialValues", func(t *testing.T) {
+		catcher := NewDedupCatcher()
+		assertCatcherEmpty(t, catcher)
+		if catcher.String() != "" {
+			t.Fatal("empty catcher should not render a string")
+		}
+	})
+	t.Run("CollapsesRepeatedErrors", func(t *testing.T) {
+		catcher := NewDedupCatcher()
+		for i := 0; i < 312; i++ {
+			catcher.New("connection refused")
+		}
+		catcher.New("timeout")
+
+		assertCatcherHasErrors(t, catcher, 2)
+		if l := catcher.Len(); l != 2 {
+			t.Fatalf("catcher should have two groups, not %d", l)
+		}
+		if total := catcher.Total(); total != 313 {
+			t.Fatalf("catcher should count every error, not %d", total)
+		}
+
+		lines := strings.Split(catcher.String(), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("unexpected output: %q", catcher.String())
+		}
+		if !strings.HasPrefix(lines[0], "connection refused (x312, first ") || !strings.Contains(lines[0], ", last ") {
+			t.Fatalf("unexpected rendering: %q", lines[0])
+		}
+		if !strings.HasPrefix(lines[1], "timeout (") || strings.Contains(lines[1], "x1") {
+			t.Fatalf("unexpected rendering: %q", lines[1])
+		}
+		if catcher.Resolve().Error() != catcher.String() {
+			t.Fatal("resolved error should match string form")
+		}
+	})
+	t.Run("KeyFunction", func(t *testing.T) {
+		catcher := MakeDedupCatcher(func(err error) string { return strings.Fields(err.Error())[0] })
+		catcher.Errorf("request %d failed", 1)
+		catcher.Errorf("request %d failed", 2)
+		catcher.New("other")
+
+		if l := catcher.Len(); l != 2 {
+			t.Fatalf("catcher should have two groups, not %d", l)
+		}
+		if !strings.HasPrefix(catcher.String(), "request 1 failed (x2") {
+			t.Fatalf("group should report the first error: %q", catcher.String())
+		}
+	})
+	t.Run("TimestampAnnotations", func(t *testing.T) {
+		first := time.Date(2021, 1, 1, 10, 1, 2, 0, time.UTC)
+		last := time.Date(2021, 1, 1, 10, 5, 40, 0, time.UTC)
+
+		catcher := NewDedupCatcher()
+		catcher.Add(&timestampError{err: errors.New("connection refused"), time: first})
+		catcher.Add(&timestampError{err: errors.New("connection refused"), time: last})
+		catcher.Add(&timestampError{err: errors.New("connection refused"), time: first.Add(time.Minute)})
+
+		if out := catcher.String(); out != "connection refused (x3, first 10:01:02, last 10:05:40)" {
+			t.Fatalf("unexpected rendering: %q", out)
+		}
+
+		errs := catcher.Errors()
+		if len(errs) != 1 {
+			t.Fatalf("catcher has %d groups", len(errs))
+		}
+		ts, ok := ErrorTimeFinder(errs[0])
+		if !ok || !ts.Equal(first) {
+			t.Fatalf("error should report the first-seen time, not %v", ts)
+		}
+
+		group := errs[0].(interface {
+			Count() int
+			FirstSeen() time.Time
+			LastSeen() time.Time
+		})
+		if group.Count() != 3 || !group.FirstSeen().Equal(first) || !group.LastSeen().Equal(last) {
+			t.Fatalf("unexpected group %d, %v, %v", group.Count(), group.FirstSeen(), group.LastSeen())
+		}
+	})
+	t.Run("TimestampOptions", func(t *testing.T) {
+		first := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		clock := NewFakeClock(first)
+
+		catcher := NewDedupCatcher(WithClock(clock))
+		catcher.New("one")
+		if msg := catcher.Errors()[0].Error(); !strings.HasSuffix(msg, "(12:00:00)") {
+			t.Fatalf("groups should render times of day by default: %q", msg)
+		}
+
+		zone := time.FixedZone("east", 3*60*60)
+		catcher = NewDedupCatcher(WithClock(clock), WithTimestampLayout(time.RFC3339Nano), WithTimestampLocation(zone))
+		catcher.New("one")
+		clock.Advance(time.Second + time.Millisecond)
+		catcher.New("one")
+
+		last := first.Add(time.Second + time.Millisecond)
+		expected := fmt.Sprintf("(x2, first %s, last %s)", first.In(zone).Format(time.RFC3339Nano), last.In(zone).Format(time.RFC3339Nano))
+		if msg := catcher.Errors()[0].Error(); !strings.HasSuffix(msg, expected) {
+			t.Fatalf("groups should render times with the layout and location: %q", msg)
+		}
+
+		wrapped := WrapErrorTime(errors.New("wrapped"), WithClock(NewFakeClock(first)))
+		inner := NewTimestampCatcher(WithClock(NewFakeClock(first)))
+		inner.New("flattened")
+
+		catcher = NewDedupCatcher(WithTimestampLayout(time.RFC3339Nano), WithTimestampLocation(zone))
+		catcher.Add(wrapped)
+		catcher.Add(inner)
+		expected = fmt.Sprintf("(%s)", first.In(zone).Format(time.RFC3339Nano))
+		for _, err := range catcher.Errors() {
+			if msg := err.Error(); !strings.HasSuffix(msg, expected) {
+				t.Fatalf("groups of timestamped errors should render with the layout and location: %q", msg)
+			}
+		}
+		if ts, ok := ErrorTimeFinder(catcher.Errors()[0]); !ok || !ts.Equal(first) {
+			t.Fatalf("groups should keep the time of timestamped errors: %s", ts)
+		}
+
+		local := time.Date(2024, 1, 1, 15, 0, 0, 0, zone)
+		catcher = NewDedupCatcher(WithUTCTimestamps())
+		catcher.Add(WrapErrorTime(errors.New("local"), WithClock(NewFakeClock(local))))
+		if msg := catcher.Errors()[0].Error(); !strings.HasSuffix(msg, "(12:00:00)") {
+			t.Fatalf("groups of timestamped errors should convert to UTC: %q", msg)
+		}
+
+		catcher = NewDedupCatcher(WithClock(NewFakeClock(first)), WithTimestampLocation(zone))
+		catcher.New("one")
+		if msg := catcher.Errors()[0].Error(); !strings.HasSuffix(msg, "(15:00:00)") {
+			t.Fatalf("groups should render times of day in the location: %q", msg)
+		}
+	})
+	t.Run("ErrorsAreStable", func(t *testing.T) {
+		catcher := NewDedupCatcher()
+		catcher.New("one")
+		err := catcher.Resolve()
+		catcher.New("one")
+
+		if strings.Contains(err.Error(), "x2") {
+			t.Fatalf("resolved error changed after resolution: %v", err)
+		}
+	})
+	t.Run("UnwrapsConstituents", func(t *testing.T) {
+		sentinel := errors.New("sentinel")
+		catcher := NewDedupCatcher()
+		catcher.Add(fmt.Errorf("wrapped: %w", sentinel))
+		catcher.Add(fmt.Errorf("wrapped: %w", sentinel))
+
+		if !errors.Is(catcher.Resolve(), sentinel) {
+			t.Fatal("resolved error should unwrap to the sentinel")
+		}
+	})
+	t.Run("DrainAndReset", func(t *testing.T) {
+		catcher := NewDedupCatcher()
+		catcher.New("one")
+		catcher.New("one")
+
+		if err := catcher.Drain(); err == nil || !strings.Contains(err.Error(), "x2") {
+			t.Fatalf("unexpected drained error: %v", err)
+		}
+		assertCatcherEmpty(t, catcher)
+		if catcher.Total() != 0 {
+			t.Fatal("drain should reset counters")
+		}
+
+		catcher.New("one")
+		if strings.Contains(catcher.String(), "x2") {
+			t.Fatal("groups should not survive a drain")
+		}
+		catcher.Reset()
+		assertCatcherEmpty(t, catcher)
+	})
+	t.Run("Helpers", func(t *testing.T) {
+		catcher := NewDedupCatcher()
+		catcher.AddWhen(false, errors.New("one"))
+		catcher.NewWhen(false, "one")
+		catcher.ErrorfWhen(false, "%s", "one")
+		catcher.ExtendWhen(false, []error{errors.New("one")})
+		catcher.CheckWhen(false, func() error { return errors.New("one") })
+		assertCatcherEmpty(t, catcher)
+
+		catcher.Extend([]error{errors.New("one"), nil, errors.New("two")})
+		catcher.CheckExtend([]CheckFunction{
+			func() error { return errors.New("one") },
+			func() error { return nil },
+		})
+		catcher.Check(func() error { return errors.New("three") })
+		catcher.Errorf("%s", "two")
+
+		if catcher.Len() != 3 || catcher.Total() != 5 {
+			t.Fatalf("unexpected state, len=%d, total=%d", catcher.Len(), catcher.Total())
+		}
+	})
+}
@@ -0,0 +1,56 @@
+//go:build go1.20
+
+package emt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCatcherJoinedErrors(t *testing.T) {
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("AddFlattensJoin", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.Add(errors.Join(errors.New("one"), errors.Join(errors.New("two"), errors.New("three"))))
+				if catcher.Len() != 3 {
+					t.Fatalf("catcher should collect the joined errors, has %d", catcher.Len())
+				}
+
+				inner := NewBasicCatcher()
+				inner.New("four")
+				catcher.Add(errors.Join(inner, errors.New("five")))
+				if catcher.Len() != 5 {
+					t.Fatalf("catcher should flatten catchers in joined errors, has %d", catcher.Len())
+				}
+				if errs := catcher.Errors(); !strings.Contains(errs[0].Error(), "one") || !strings.Contains(errs[4].Error(), "five") {
+					t.Fatalf("flattening should retain order: %v", errs)
+				}
+			})
+			t.Run("ExtendFlattensJoin", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.Extend([]error{errors.New("zero"), errors.Join(errors.New("one"), nil, errors.New("two"))})
+				if catcher.Len() != 3 {
+					t.Fatalf("catcher should collect the joined errors, has %d", catcher.Len())
+				}
+			})
+			t.Run("AddKeepsWrappedErrors", func(t *testing.T) {
+				one, two := errors.New("one"), errors.New("two")
+
+				catcher := fix.Factory()
+				catcher.Add(fmt.Errorf("while syncing shard 3: %w; %w", one, two))
+				if catcher.Len() != 1 {
+					t.Fatalf("catcher should collect the error as one error, has %d", catcher.Len())
+				}
+				if !strings.Contains(catcher.Errors()[0].Error(), "while syncing shard 3") {
+					t.Fatalf("catcher should keep the context of the error: %v", catcher.Errors()[0])
+				}
+				if !errors.Is(catcher.Resolve(), one) || !errors.Is(catcher.Resolve(), two) {
+					t.Fatal("errors.Is should find the wrapped errors")
+				}
+			})
+		})
+	}
+}
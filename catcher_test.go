@@ -1,14 +1,41 @@
 package emt
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
+// catcherFixture constructs one kind of catcher with options, for
+// the tests of features that all catchers share.
+type catcherFixture struct {
+	Name    string
+	Factory func(...CatcherOption) Catcher
+}
+
+func catcherFixtures() []catcherFixture {
+	return append([]catcherFixture{
+		{Name: "Basic", Factory: NewBasicCatcher},
+		{Name: "Simple", Factory: NewSimpleCatcher},
+		{Name: "Extended", Factory: NewExtendedCatcher},
+		{Name: "Timestamp", Factory: NewTimestampCatcher},
+		{Name: "ExtendedTimestamp", Factory: NewExtendedTimestampCatcher},
+		{Name: "Dedup", Factory: NewDedupCatcher},
+		{Name: "Fixed/Basic", Factory: func(opts ...CatcherOption) Catcher { return MakeBasicCatcher(10, opts...) }},
+		{Name: "Fixed/Timestamp", Factory: func(opts ...CatcherOption) Catcher { return MakeTimestampCatcher(10, opts...) }},
+		{Name: "TimeWindow", Factory: func(opts ...CatcherOption) Catcher { return MakeTimeWindowCatcher(time.Hour, opts...) }},
+	}, versionedCatcherFixtures...)
+}
+
+// versionedCatcherFixtures holds the fixtures for catchers that
+// depend on the version of Go, such as the slog catcher.
+var versionedCatcherFixtures []catcherFixture
+
 func TestCatcher(t *testing.T) {
 	type fixture struct {
 		Name      string
@@ -18,27 +45,27 @@ func TestCatcher(t *testing.T) {
 	fixtures := []fixture{
 		{
 			Name:    "Catcher",
-			Factory: NewCatcher,
+			Factory: func() Catcher { return NewCatcher() },
 		},
 		{
 			Name:    "ExtendedCatcher",
-			Factory: NewExtendedCatcher,
+			Factory: func() Catcher { return NewExtendedCatcher() },
 		},
 		{
 			Name:    "BasicCatcher",
-			Factory: NewBasicCatcher,
+			Factory: func() Catcher { return NewBasicCatcher() },
 		},
 		{
 			Name:    "Simple",
-			Factory: NewSimpleCatcher,
+			Factory: func() Catcher { return NewSimpleCatcher() },
 		},
 		{
 			Name:    "Timestamp",
-			Factory: NewTimestampCatcher,
+			Factory: func() Catcher { return NewTimestampCatcher() },
 		},
 		{
 			Name:    "ExtendedTimestamp",
-			Factory: NewExtendedTimestampCatcher,
+			Factory: func() Catcher { return NewExtendedTimestampCatcher() },
 		},
 	}
 
@@ -524,6 +551,153 @@ func TestCatcher(t *testing.T) {
 
 			},
 		},
+		{
+			Name: "ErrorsAreOrderedAfterOverflow",
+			Case: func(t *testing.T, catcher Catcher, size int) {
+				if size <= 0 {
+					size = 256
+				}
+
+				for i := 0; i < 3*size+1; i++ {
+					catcher.Add(errors.New(strconv.Itoa(i)))
+				}
+
+				errs := catcher.Errors()
+				offset := 3*size + 1 - len(errs)
+				for idx, err := range errs {
+					if msg, expected := err.Error(), strconv.Itoa(offset+idx); msg != expected && !strings.HasSuffix(msg, " "+expected) {
+						t.Fatalf("error at %d is %q, expected %d", idx, err, offset+idx)
+					}
+				}
+			},
+		},
+		{
+			Name: "DroppedAndTotal",
+			Case: func(t *testing.T, catcher Catcher, size int) {
+				if catcher.Dropped() != 0 || catcher.Total() != 0 {
+					t.Fatal("new catchers should not have counted errors")
+				}
+
+				for i := 0; i < 2000; i++ {
+					catcher.New("abc")
+				}
+
+				if total := catcher.Total(); total != 2000 {
+					t.Fatalf("catcher should have ingested 2000 errors, not %d", total)
+				}
+				if dropped := catcher.Dropped(); dropped != 2000-catcher.Len() {
+					t.Fatalf("catcher dropped %d errors and holds %d", dropped, catcher.Len())
+				}
+
+				summary := fmt.Sprintf("... and %d earlier errors omitted", catcher.Dropped())
+				if size <= 0 {
+					if strings.Contains(catcher.String(), "omitted") {
+						t.Fatalf("unbounded catcher should not report omissions: %s", catcher.String())
+					}
+					return
+				}
+
+				if !strings.HasSuffix(catcher.String(), summary) {
+					t.Fatal("string form should report dropped errors")
+				}
+				if err := catcher.Resolve(); !strings.HasSuffix(err.Error(), summary) {
+					t.Fatal("resolved error should report dropped errors")
+				}
+			},
+		},
+		{
+			Name: "ResetClearsState",
+			Case: func(t *testing.T, catcher Catcher, size int) {
+				for i := 0; i < 2000; i++ {
+					catcher.New("abc")
+				}
+				catcher.Reset()
+				assertCatcherEmpty(t, catcher)
+				if catcher.Total() != 0 || catcher.Dropped() != 0 {
+					t.Fatal("reset should clear counters")
+				}
+
+				catcher.New("abc")
+				assertCatcherHasErrors(t, catcher, 1)
+			},
+		},
+		{
+			Name: "DrainEmpty",
+			Case: func(t *testing.T, catcher Catcher, size int) {
+				if err := catcher.Drain(); err != nil {
+					t.Fatalf("empty catcher should drain nil, not %v", err)
+				}
+				if errs := catcher.Flush(); len(errs) != 0 {
+					t.Fatalf("empty catcher flushed %d errors", len(errs))
+				}
+			},
+		},
+		{
+			Name: "DrainResolvesAndClears",
+			Case: func(t *testing.T, catcher Catcher, size int) {
+				catcher.New("one")
+				catcher.New("two")
+				expected := catcher.String()
+
+				err := catcher.Drain()
+				if err == nil || err.Error() != expected {
+					t.Fatalf("drained error %v, expected %q", err, expected)
+				}
+				assertCatcherEmpty(t, catcher)
+			},
+		},
+		{
+			Name: "FlushReturnsErrorsAndClears",
+			Case: func(t *testing.T, catcher Catcher, size int) {
+				catcher.New("one")
+				catcher.New("two")
+
+				errs := catcher.Flush()
+				if len(errs) != 2 {
+					t.Fatalf("flushed %d errors", len(errs))
+				}
+				if !strings.HasSuffix(errs[1].Error(), "two") {
+					t.Fatalf("flushed errors out of order: %v", errs)
+				}
+				assertCatcherEmpty(t, catcher)
+			},
+		},
+		{
+			Name: "ConcurrentDrainLosesNothing",
+			Case: func(t *testing.T, catcher Catcher, size int) {
+				const count = 500
+				if capper, ok := catcher.(interface{ Cap() int }); ok && size > 0 && capper.Cap() < count {
+					t.Skip("catcher is too small to hold all errors")
+				}
+
+				wg := &sync.WaitGroup{}
+				wg.Add(count)
+				for i := 0; i < count; i++ {
+					go func() {
+						defer wg.Done()
+						catcher.New("abc")
+					}()
+				}
+
+				seen := 0
+				done := make(chan struct{})
+				go func() { defer close(done); wg.Wait() }()
+			DRAIN:
+				for {
+					select {
+					case <-done:
+						break DRAIN
+					default:
+						seen += len(catcher.Flush())
+					}
+				}
+				seen += len(catcher.Flush())
+
+				if seen != count {
+					t.Fatalf("flushed %d of %d errors", seen, count)
+				}
+			},
+		},
 	}
 
 	for _, fix := range fixtures {
@@ -608,3 +782,225 @@ func assertCapacityIsAtLeast(t *testing.T, catcher Catcher, size int) {
 		t.Fatalf("cp=%d, which is not greater than target %d", cp, size)
 	}
 }
+
+func TestCheckContext(t *testing.T) {
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("PassesContext", func(t *testing.T) {
+				type ctxKey struct{}
+				ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+				catcher := fix.Factory()
+				catcher.CheckCtx(ctx, func(ctx context.Context) error {
+					if ctx.Value(ctxKey{}) != "value" {
+						return errors.New("check did not receive the context")
+					}
+					return nil
+				})
+				assertCatcherEmpty(t, catcher)
+
+				catcher.CheckCtx(ctx, func(context.Context) error { return errors.New("check") })
+				assertCatcherHasErrors(t, catcher, 1)
+			})
+			t.Run("CanceledContextSkipsCheck", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				called := false
+				catcher := fix.Factory()
+				catcher.CheckCtx(ctx, func(context.Context) error { called = true; return nil })
+
+				if called {
+					t.Fatal("check should not run with a canceled context")
+				}
+				if !errors.Is(catcher.Resolve(), context.Canceled) {
+					t.Fatalf("catcher should collect the context error: %v", catcher.Resolve())
+				}
+			})
+			t.Run("CheckCtxWhen", func(t *testing.T) {
+				ctx := context.Background()
+				fn := func(context.Context) error { return errors.New("check") }
+
+				catcher := fix.Factory()
+				catcher.CheckCtxWhen(ctx, false, fn)
+				assertCatcherEmpty(t, catcher)
+
+				catcher.CheckCtxWhen(ctx, true, fn)
+				assertCatcherHasErrors(t, catcher, 1)
+			})
+			t.Run("CheckCtxExtendStopsAfterCancel", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				count := 0
+				fn := func(context.Context) error { count++; return fmt.Errorf("check %d", count) }
+
+				catcher := fix.Factory()
+				catcher.CheckCtxExtend(ctx, []ContextCheckFunction{
+					fn,
+					func(context.Context) error { cancel(); return nil },
+					fn,
+					fn,
+				})
+
+				if count != 1 {
+					t.Fatalf("checks should not run after cancellation, ran %d", count)
+				}
+				if catcher.Total() != 2 {
+					t.Fatalf("catcher should have the error and the context error, has %d", catcher.Total())
+				}
+				if !errors.Is(catcher.Resolve(), context.Canceled) {
+					t.Fatalf("catcher should collect the context error: %v", catcher.Resolve())
+				}
+			})
+			t.Run("Timeout", func(t *testing.T) {
+				catcher := fix.Factory(WithCheckTimeout(time.Millisecond))
+				catcher.CheckCtx(context.Background(), func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				})
+
+				var terr *CheckTimeoutError
+				if !errors.As(catcher.Resolve(), &terr) {
+					t.Fatalf("catcher should collect a timeout error: %v", catcher.Resolve())
+				}
+				if terr.Timeout != time.Millisecond {
+					t.Fatalf("unexpected timeout %s", terr.Timeout)
+				}
+				if !errors.Is(terr, context.DeadlineExceeded) {
+					t.Fatal("timeout error should wrap the error from the check")
+				}
+			})
+			t.Run("TimeoutNotExceeded", func(t *testing.T) {
+				catcher := fix.Factory(WithCheckTimeout(time.Minute))
+				catcher.CheckCtx(context.Background(), func(ctx context.Context) error {
+					if _, ok := ctx.Deadline(); !ok {
+						return errors.New("check context should have a deadline")
+					}
+					return nil
+				})
+				assertCatcherEmpty(t, catcher)
+			})
+			t.Run("TimeoutWithPanicRecovery", func(t *testing.T) {
+				catcher := fix.Factory(WithCheckTimeout(time.Minute), WithPanicRecovery())
+				catcher.CheckCtx(context.Background(), func(context.Context) error { panic("boom") })
+
+				var perr *PanicError
+				if !errors.As(catcher.Resolve(), &perr) {
+					t.Fatalf("panic should be collected: %v", catcher.Resolve())
+				}
+			})
+		})
+	}
+}
+
+type multiError struct{ errs []error }
+
+func (e *multiError) Error() string   { return fmt.Sprint(e.errs) }
+func (e *multiError) Unwrap() []error { return e.errs }
+
+func TestCatcherAsError(t *testing.T) {
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("Error", func(t *testing.T) {
+				catcher := fix.Factory()
+				var err error = catcher
+				if err.Error() != "" {
+					t.Fatalf("empty catcher should render as empty string: %q", err)
+				}
+
+				catcher.New("one")
+				catcher.New("two")
+				if err.Error() != catcher.String() {
+					t.Fatalf("error %q should match string %q", err.Error(), catcher.String())
+				}
+				if fmt.Sprint(err) != catcher.String() {
+					t.Fatalf("formatted %q should match string %q", fmt.Sprint(err), catcher.String())
+				}
+			})
+			t.Run("Unwrap", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.Add(context.Canceled)
+				catcher.New("one")
+
+				if len(catcher.Unwrap()) != 2 {
+					t.Fatalf("catcher unwrapped %d errors", len(catcher.Unwrap()))
+				}
+				if !errors.Is(catcher, context.Canceled) {
+					t.Fatal("errors.Is should find errors in the catcher")
+				}
+				if errors.Is(catcher, context.DeadlineExceeded) {
+					t.Fatal("errors.Is should not find missing errors")
+				}
+			})
+			t.Run("AddFlattensCatchers", func(t *testing.T) {
+				inner := NewBasicCatcher()
+				inner.New("one")
+				inner.New("two")
+
+				catcher := fix.Factory()
+				catcher.Add(inner)
+				if catcher.Len() != 2 {
+					t.Fatalf("catcher should collect the constituents, has %d", catcher.Len())
+				}
+
+				catcher.Add(NewBasicCatcher())
+				if catcher.Len() != 2 {
+					t.Fatalf("adding an empty catcher should not add errors, has %d", catcher.Len())
+				}
+			})
+			t.Run("AddFlattensAggregates", func(t *testing.T) {
+				other := NewBasicCatcher()
+				other.New("one")
+				other.New("two")
+
+				catcher := fix.Factory()
+				catcher.Add(other.Resolve())
+				if catcher.Len() != 2 {
+					t.Fatalf("catcher should collect the constituents of aggregates, has %d", catcher.Len())
+				}
+			})
+			t.Run("AddKeepsMultiErrors", func(t *testing.T) {
+				one, two := errors.New("one"), errors.New("two")
+
+				catcher := fix.Factory()
+				catcher.Add(&multiError{errs: []error{one, two}})
+				catcher.Add(&multiError{errs: []error{errors.New("three")}})
+				if catcher.Len() != 2 {
+					t.Fatalf("catcher should collect multi-errors as one error, has %d", catcher.Len())
+				}
+				if !errors.Is(catcher.Resolve(), one) || !errors.Is(catcher.Resolve(), two) {
+					t.Fatal("errors.Is should find the wrapped errors")
+				}
+
+				var merr *multiError
+				if !errors.As(catcher.Resolve(), &merr) {
+					t.Fatal("errors.As should find the multi-error")
+				}
+			})
+			t.Run("ExtendFlattens", func(t *testing.T) {
+				inner := fix.Factory()
+				inner.New("one")
+				inner.New("two")
+
+				catcher := fix.Factory()
+				catcher.Extend([]error{errors.New("zero"), inner, nil, errors.New("three")})
+				if catcher.Len() != 4 {
+					t.Fatalf("catcher should collect the constituents, has %d", catcher.Len())
+				}
+				if errs := catcher.Errors(); !strings.Contains(errs[0].Error(), "zero") || !strings.Contains(errs[3].Error(), "three") {
+					t.Fatalf("flattening should retain order: %v", errs)
+				}
+			})
+			t.Run("AddSelf", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("one")
+				catcher.Add(catcher)
+
+				if catcher.Total() != 2 {
+					t.Fatalf("adding a catcher to itself should add its errors, has %d", catcher.Total())
+				}
+			})
+		})
+	}
+}
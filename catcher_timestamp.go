@@ -1,9 +1,9 @@
 package emt
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
 )
@@ -47,6 +47,8 @@ type timestampError struct {
 	err      error
 	time     time.Time
 	extended bool
+	layout   string
+	loc      *time.Location
 }
 
 func newTimeStampError(err error) *timestampError {
@@ -54,37 +56,88 @@ func newTimeStampError(err error) *timestampError {
 		return nil
 	}
 
-	switch v := err.(type) {
-	case *timestampError:
-		return v
-	default:
-		return &timestampError{
-			err:  err,
-			time: time.Now(),
-		}
+	conf := makeCatcherOptions(nil)
+	return conf.annotateTime(err)
+}
+
+// annotateTime annotates the error with the current time, and with
+// the timestamp layout and location of the options. Errors that are
+// already annotated keep their time, and take the layout, location
+// and UTC conversion of the options, when set, in a copy.
+func (conf *catcherOptions) annotateTime(err error) *timestampError {
+	tserr, ok := err.(*timestampError)
+	if !ok {
+		return &timestampError{err: err, time: conf.now(), layout: conf.layout, loc: conf.loc}
+	}
+
+	if conf.layout == "" && conf.loc == nil && !conf.utc {
+		return tserr
+	}
+
+	out := *tserr
+	if conf.utc {
+		out.time = out.time.UTC()
+	}
+	if conf.layout != "" {
+		out.layout = conf.layout
+	}
+	if conf.loc != nil {
+		out.loc = conf.loc
+	}
+
+	return &out
+}
+
+// timestamp renders the time of the error with the layout and in the
+// location of the error, by default RFC3339 in the location of the
+// time.
+func (e *timestampError) timestamp() string { return e.formatTime(e.time, time.RFC3339) }
+
+// formatTime renders the time with the layout and location of the
+// annotation, using the default layout when the annotation has none.
+func (e *timestampError) formatTime(ts time.Time, layout string) string {
+	if e.loc != nil {
+		ts = ts.In(e.loc)
+	}
+
+	if e.layout != "" {
+		layout = e.layout
 	}
+
+	return ts.Format(layout)
 }
 
 func (e *timestampError) setExtended(v bool) *timestampError { e.extended = v; return e }
 
 // WrapErrorTime annotates an error with the timestamp. The underlying
-// concrete object implements message.Composer as well as error.
-func WrapErrorTime(err error) error { return newTimeStampError(err) }
+// concrete object implements message.Composer as well as error. The
+// WithClock, WithUTCTimestamps, WithTimestampLayout and
+// WithTimestampLocation options control the timestamp.
+func WrapErrorTime(err error, opts ...CatcherOption) error {
+	if err == nil {
+		return nil
+	}
+
+	conf := makeCatcherOptions(opts)
+	return conf.annotateTime(err)
+}
 
 // WrapErrorTimeMessage annotates an error with the timestamp and a
 // string form. The underlying concrete object implements
-// message.Composer as well as error.
-func WrapErrorTimeMessage(err error, m string) error {
+// message.Composer as well as error. The options control the
+// timestamp, as with WrapErrorTime.
+func WrapErrorTimeMessage(err error, m string, opts ...CatcherOption) error {
 	if err == nil {
 		return nil
 	}
-	return newTimeStampError(fmt.Errorf("%s: %w", m, err))
+	return WrapErrorTime(fmt.Errorf("%s: %w", m, err), opts...)
 }
 
 // WrapErrorTimeMessagef annotates an error with a timestamp and a
 // string formated message, like fmt.Sprintf or fmt.Errorf. The
 // underlying concrete object implements  message.Composer as well as
-// error.
+// error. The timestamp comes from the system clock: to use another
+// clock, pass the output of fmt.Sprintf to WrapErrorTimeMessage.
 func WrapErrorTimeMessagef(err error, m string, args ...interface{}) error {
 	return WrapErrorTimeMessage(err, fmt.Sprintf(m, args...))
 }
@@ -104,20 +157,21 @@ func (e *timestampError) String() string {
 func (e *timestampError) Cause() error  { return e.err }
 func (e *timestampError) Unwrap() error { return e.err }
 func (e *timestampError) Error() string {
-	return fmt.Sprintf("[%s], %s", e.time.Format(time.RFC3339), e.String())
+	return fmt.Sprintf("[%s], %s", e.timestamp(), e.String())
 }
 
 func (e *timestampError) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if s.Flag('+') {
-			_, _ = fmt.Fprintf(s, "[%s] %+v", e.time.Format(time.RFC3339), e.Cause())
+			_, _ = fmt.Fprintf(s, "[%s] %+v", e.timestamp(), e.Cause())
+			return
 		}
 		fallthrough
 	case 's':
-		_, _ = fmt.Fprintf(s, "[%s] %s", e.time.Format(time.RFC3339), e.String())
+		_, _ = fmt.Fprintf(s, "[%s] %s", e.timestamp(), e.String())
 	case 'q':
-		_, _ = fmt.Fprintf(s, "[%s] %q", e.time.Format(time.RFC3339), e.String())
+		_, _ = fmt.Fprintf(s, "[%s] %q", e.timestamp(), e.String())
 	}
 }
 
@@ -127,33 +181,32 @@ func (e *timestampError) Format(s fmt.State, verb rune) {
 
 type timeAnnotatingCatcher struct {
 	mu       sync.RWMutex
-	errs     []*timestampError
-	maxSize  int
+	errs     errorRing
 	extended bool
+	window   time.Duration
+	opts     catcherOptions
 }
 
 // NewTimestampCatcher produces a Catcher instance that reports the
 // short form of all constituent errors and annotates those errors
 // with a timestamp to reflect when the error was collected.
-func NewTimestampCatcher() Catcher { return MakeTimestampCatcher(0) }
+func NewTimestampCatcher(opts ...CatcherOption) Catcher { return MakeTimestampCatcher(0, opts...) }
 
 // NewExtendedTimestampCatcher adds long-form annotation to the
 // aggregated error message (e.g. including stacks, when possible.)
-func NewExtendedTimestampCatcher() Catcher { return MakeExtendedTimestampCatcher(0) }
+func NewExtendedTimestampCatcher(opts ...CatcherOption) Catcher {
+	return MakeExtendedTimestampCatcher(0, opts...)
+}
 
 // MakeTimestampCatcher constructs a Catcher instance that annotates
 // all errors with their collection time; however, if the size is
 // greater than 0 the catcher will never collect more than the
 // specified number of errors, discarding earlier messages when adding
 // new messages.
-func MakeTimestampCatcher(size int) Catcher {
-	if size < 0 {
-		size = 0
-	}
-
+func MakeTimestampCatcher(size int, opts ...CatcherOption) Catcher {
 	return &timeAnnotatingCatcher{
-		errs:    make([]*timestampError, 0, size),
-		maxSize: size,
+		errs: makeErrorRing(size),
+		opts: makeCatcherOptions(opts),
 	}
 }
 
@@ -162,40 +215,113 @@ func MakeTimestampCatcher(size int) Catcher {
 // possible. If the size greater than 0 the catcher will never collect
 // more than the specified number of errors, discarding earlier
 // messages when adding new messages.
-func MakeExtendedTimestampCatcher(size int) Catcher {
-	if size < 0 {
-		size = 0
-	}
+func MakeExtendedTimestampCatcher(size int, opts ...CatcherOption) Catcher {
 	return &timeAnnotatingCatcher{
-		errs:     make([]*timestampError, 0, size),
-		maxSize:  size,
+		errs:     makeErrorRing(size),
 		extended: true,
+		opts:     makeCatcherOptions(opts),
+	}
+}
+
+// MakeTimeWindowCatcher constructs a Catcher instance that annotates
+// all errors with their collection time, as NewTimestampCatcher, and
+// discards errors collected more than the window ago, so that Len,
+// Errors, Resolve and the other methods report only the errors
+// collected within the window. The catcher counts the discarded
+// errors in Dropped, but String, Error and Resolve do not report them
+// as omitted, and render as empty when every error expired. Use the
+// WithClock option to control the time that the catcher uses to
+// annotate errors and to find expired errors. If the window is less
+// than or equal to zero, errors never expire.
+func MakeTimeWindowCatcher(window time.Duration, opts ...CatcherOption) Catcher {
+	return &timeAnnotatingCatcher{
+		errs:   makeErrorRing(0),
+		window: window,
+		opts:   makeCatcherOptions(opts),
+	}
+}
+
+func (c *timeAnnotatingCatcher) emptyCopy() Catcher {
+	return &timeAnnotatingCatcher{
+		errs:     makeErrorRing(c.errs.limit),
+		extended: c.extended,
+		window:   c.window,
+		opts:     c.opts,
 	}
 }
 
+// expire removes the errors collected before the window, for
+// catchers with a window. The read methods call expire before
+// acquiring the read lock; expire only acquires the write lock when
+// there are expired errors.
+func (c *timeAnnotatingCatcher) expire() {
+	if c.window <= 0 {
+		return
+	}
+
+	c.mu.RLock()
+	cutoff := c.cutoff()
+	expired := c.safeExpired(cutoff)
+	c.mu.RUnlock()
+
+	if expired == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errs.expireOldest(c.safeExpired(cutoff))
+}
+
+func (c *timeAnnotatingCatcher) safeExpire() {
+	if c.window <= 0 {
+		return
+	}
+
+	c.errs.expireOldest(c.safeExpired(c.cutoff()))
+}
+
+// cutoff returns the time before which errors expire.
+func (c *timeAnnotatingCatcher) cutoff() time.Time { return c.opts.now().Add(-c.window) }
+
+// safeExpired returns the number of errors, from the oldest, that
+// were collected before the cutoff. Errors expire in the order that
+// the catcher collected them, so an error that already had an older
+// timestamp when it was added expires with the errors after it.
+func (c *timeAnnotatingCatcher) safeExpired(cutoff time.Time) int {
+	n := 0
+	for n < c.errs.len() && c.errs.at(n).(*timestampError).time.Before(cutoff) {
+		n++
+	}
+
+	return n
+}
+
 func (c *timeAnnotatingCatcher) Add(err error) {
 	if err == nil {
 		return
+	} else if errs, ok := aggregatedErrors(err); ok {
+		c.Extend(errs)
+		return
 	}
 
+	err = c.opts.annotate(err, c.opts.stack())
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.safeAdd(err)
+	c.safeExpire()
 }
 
 func (c *timeAnnotatingCatcher) safeAdd(err error) {
 	switch e := err.(type) {
 	case nil:
 	case *timestampError:
-		if c.maxSize <= 0 || c.maxSize > len(c.errs) {
-			c.errs = append(c.errs, e)
-		} else {
-			c.errs = c.errs[1:]
-			c.errs = append(c.errs, e)
-		}
-	case error:
-		c.safeAdd(newTimeStampError(e).setExtended(c.extended))
+		c.errs.push(c.opts.annotateTime(e))
+	default:
+		c.errs.push(c.opts.annotateTime(e).setExtended(c.extended))
 	}
 }
 
@@ -204,6 +330,9 @@ func (c *timeAnnotatingCatcher) Extend(errs []error) {
 		return
 	}
 
+	errs = flattenErrors(errs)
+	pcs := c.opts.stack()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -212,10 +341,14 @@ func (c *timeAnnotatingCatcher) Extend(errs []error) {
 			continue
 		}
 
-		c.safeAdd(newTimeStampError(err).setExtended(c.extended))
+		c.safeAdd(c.opts.annotate(err, pcs))
 	}
+
+	c.safeExpire()
 }
 
+func (c *timeAnnotatingCatcher) AddLevel(level Level, err error) { c.Add(WrapErrorLevel(level, err)) }
+
 func (c *timeAnnotatingCatcher) AddWhen(cond bool, err error) {
 	if !cond {
 		return
@@ -268,7 +401,7 @@ func (c *timeAnnotatingCatcher) ErrorfWhen(cond bool, f string, args ...interfac
 }
 
 func (c *timeAnnotatingCatcher) Check(fn CheckFunction) {
-	c.Add(fn())
+	c.Add(c.opts.check(fn))
 }
 
 func (c *timeAnnotatingCatcher) CheckWhen(cond bool, fn CheckFunction) {
@@ -276,69 +409,179 @@ func (c *timeAnnotatingCatcher) CheckWhen(cond bool, fn CheckFunction) {
 		return
 	}
 
-	c.Add(fn())
+	c.Add(c.opts.check(fn))
 }
 
 func (c *timeAnnotatingCatcher) CheckExtend(fns []CheckFunction) {
 	for _, fn := range fns {
-		c.Add(fn())
+		c.Add(c.opts.check(fn))
+	}
+}
+
+func (c *timeAnnotatingCatcher) CheckCtx(ctx context.Context, fn ContextCheckFunction) {
+	c.Add(c.opts.checkCtx(ctx, fn))
+}
+
+func (c *timeAnnotatingCatcher) CheckCtxWhen(ctx context.Context, cond bool, fn ContextCheckFunction) {
+	if !cond {
+		return
+	}
+
+	c.Add(c.opts.checkCtx(ctx, fn))
+}
+
+func (c *timeAnnotatingCatcher) CheckCtxExtend(ctx context.Context, fns []ContextCheckFunction) {
+	for _, fn := range fns {
+		if err := ctx.Err(); err != nil {
+			c.Add(err)
+			return
+		}
+
+		c.Add(c.opts.checkCtx(ctx, fn))
 	}
 }
 
 func (c *timeAnnotatingCatcher) Len() int {
+	c.expire()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	return len(c.errs)
+	return c.errs.len()
 }
 
 func (c *timeAnnotatingCatcher) Cap() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	return cap(c.errs)
+	return c.errs.cap()
+}
+
+func (c *timeAnnotatingCatcher) Dropped() int {
+	c.expire()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.errs.dropped + c.errs.expired
+}
+
+func (c *timeAnnotatingCatcher) Total() int {
+	c.expire()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.errs.total()
 }
 
 func (c *timeAnnotatingCatcher) HasErrors() bool {
+	c.expire()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	return len(c.errs) > 0
+	return c.errs.len() > 0
 }
 
-func (c *timeAnnotatingCatcher) Errors() []error {
+func (c *timeAnnotatingCatcher) HasErrorsAtLeast(threshold Level) bool {
+	c.expire()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	out := make([]error, len(c.errs))
-	for idx, err := range c.errs {
-		out[idx] = err
-	}
+	return hasLevel(c.errs.slice(), threshold)
+}
 
-	return out
+func (c *timeAnnotatingCatcher) Errors() []error {
+	c.expire()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.errs.slice()
 }
 
 func (c *timeAnnotatingCatcher) String() string {
+	c.expire()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	output := make([]string, len(c.errs))
+	return joinErrors(c.errs.slice(), c.errs.dropped, renderTimestamp, c.opts.formatter)
+}
 
-	for idx, err := range c.errs {
-		if err.extended {
-			output[idx] = err.String()
-		} else {
-			output[idx] = err.String()
-		}
+func (c *timeAnnotatingCatcher) Error() string {
+	if c == nil {
+		return ""
 	}
 
-	return strings.Join(output, "\n")
+	return c.String()
+}
+
+func (c *timeAnnotatingCatcher) Unwrap() []error {
+	if c == nil {
+		return nil
+	}
+
+	return c.Errors()
 }
 
 func (c *timeAnnotatingCatcher) Resolve() error {
-	if !c.HasErrors() {
+	c.expire()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.errs.len() == 0 {
 		return nil
 	}
 
-	return errors.New(c.String())
+	return &AggregateError{errs: c.errs.slice(), dropped: c.errs.dropped, render: renderTimestamp, format: c.opts.formatter}
+}
+
+func (c *timeAnnotatingCatcher) ResolveAtLeast(threshold Level) error {
+	c.expire()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	errs := filterLevel(c.errs.slice(), threshold)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &AggregateError{errs: errs, render: renderTimestamp, format: c.opts.formatter}
+}
+
+func (c *timeAnnotatingCatcher) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errs.reset()
+}
+
+func (c *timeAnnotatingCatcher) Drain() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.safeExpire()
+
+	if c.errs.len() == 0 {
+		return nil
+	}
+
+	defer c.errs.reset()
+
+	return &AggregateError{errs: c.errs.slice(), dropped: c.errs.dropped, render: renderTimestamp, format: c.opts.formatter}
+}
+
+func (c *timeAnnotatingCatcher) Flush() []error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.safeExpire()
+
+	defer c.errs.reset()
+
+	return c.errs.slice()
+}
+
+func renderTimestamp(err error) string {
+	if tserr, ok := err.(*timestampError); ok {
+		return tserr.String()
+	}
+
+	return err.Error()
 }
@@ -228,16 +228,74 @@ func TestTimestampError(t *testing.T) {
 			}
 			for _, fstr := range []string{"%+v", "%v", "%s", "%q"} {
 				t.Run(fstr, func(t *testing.T) {
-					if !strings.Contains(fmt.Sprintf("%+v", err), err.time.Format(time.RFC3339)) {
+					if !strings.Contains(fmt.Sprintf(fstr, err), err.time.Format(time.RFC3339)) {
 						t.Fatalf("timestamp is not present in [%s]: %v", fstr, err)
 					}
-					if !strings.Contains(fmt.Sprintf("%+v", err), "hello world") {
+					if !strings.Contains(fmt.Sprintf(fstr, err), "hello world") {
 						t.Fatalf("error string is not present [%s]: %v", fstr, err)
 					}
 				})
 			}
 		})
 	})
+	t.Run("FormattingVerbs", func(t *testing.T) {
+		ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		for _, tc := range []struct {
+			Name     string
+			Err      error
+			Expected map[string]string
+		}{
+			{
+				Name: "Basic",
+				Err:  &timestampError{time: ts, err: &detailedError{msg: "hello", detail: "at main.go:12"}},
+				Expected: map[string]string{
+					"%s":  "[2024-01-01T12:00:00Z] hello",
+					"%v":  "[2024-01-01T12:00:00Z] hello",
+					"%+v": "[2024-01-01T12:00:00Z] hello\n\tat main.go:12",
+					"%q":  `[2024-01-01T12:00:00Z] "hello"`,
+				},
+			},
+			{
+				Name: "Extended",
+				Err:  &timestampError{time: ts, err: &detailedError{msg: "hello", detail: "at main.go:12"}, extended: true},
+				Expected: map[string]string{
+					"%s":  "[2024-01-01T12:00:00Z] hello\n\tat main.go:12",
+					"%v":  "[2024-01-01T12:00:00Z] hello\n\tat main.go:12",
+					"%+v": "[2024-01-01T12:00:00Z] hello\n\tat main.go:12",
+					"%q":  `[2024-01-01T12:00:00Z] "hello\n\tat main.go:12"`,
+				},
+			},
+		} {
+			t.Run(tc.Name, func(t *testing.T) {
+				for verb, expected := range tc.Expected {
+					if out := fmt.Sprintf(verb, tc.Err); out != expected {
+						t.Errorf("%s rendered %q, expected %q", verb, out, expected)
+					}
+				}
+			})
+		}
+	})
+	t.Run("AggregateFormattingVerbs", func(t *testing.T) {
+		ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		catcher := NewTimestampCatcher(WithClock(NewFakeClock(ts)))
+		catcher.Add(&detailedError{msg: "one", detail: "at one.go:1"})
+		catcher.New("two")
+		err := catcher.Resolve()
+
+		for verb, expected := range map[string]string{
+			"%s":  "one\ntwo",
+			"%v":  "one\ntwo",
+			"%+v": "[2024-01-01T12:00:00Z] one\n\tat one.go:1\n[2024-01-01T12:00:00Z] two",
+			"%q":  `"one\ntwo"`,
+		} {
+			if out := fmt.Sprintf(verb, err); out != expected {
+				t.Errorf("%s rendered %q, expected %q", verb, out, expected)
+			}
+		}
+		if fmt.Sprint(err) != err.Error() {
+			t.Errorf("%%v should match the message, %q", err)
+		}
+	})
 	t.Run("QuotedFormatting", func(t *testing.T) {
 		err := newTimeStampError(fmt.Errorf("hello"))
 		if strings.Contains(err.Error(), `"hello"`) {
@@ -265,3 +323,330 @@ func TestTimestampError(t *testing.T) {
 	})
 
 }
+
+func TestTimeWindowCatcher(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("UsesClock", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		catcher := MakeTimeWindowCatcher(time.Minute, WithClock(clock))
+		catcher.New("hello")
+
+		ts, ok := ErrorTimeFinder(catcher.Errors()[0])
+		if !ok || !ts.Equal(start) {
+			t.Fatalf("error should have the clock's time, not %s", ts)
+		}
+	})
+	t.Run("ReadsWithoutExpiryDoNotAllocate", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		catcher := MakeTimeWindowCatcher(5*time.Minute, WithClock(clock))
+		for i := 0; i < 100; i++ {
+			catcher.New("hello")
+		}
+
+		if allocs := testing.AllocsPerRun(100, func() { _ = catcher.Len() }); allocs != 0 {
+			t.Fatalf("reading a catcher without expired errors allocated %v times", allocs)
+		}
+	})
+	t.Run("ExpiresOnRead", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		catcher := MakeTimeWindowCatcher(5*time.Minute, WithClock(clock))
+		catcher.New("one")
+		clock.Advance(3 * time.Minute)
+		catcher.New("two")
+
+		if catcher.Len() != 2 {
+			t.Fatalf("catcher should have both errors, has %d", catcher.Len())
+		}
+
+		clock.Advance(3 * time.Minute)
+		if catcher.Len() != 1 {
+			t.Fatalf("the first error should expire, has %d", catcher.Len())
+		}
+		if errs := catcher.Errors(); len(errs) != 1 || !strings.Contains(errs[0].Error(), "two") {
+			t.Fatalf("catcher should only have live errors: %v", errs)
+		}
+		if err := catcher.Resolve(); err == nil || strings.Contains(err.Error(), "one") {
+			t.Fatalf("resolved error should only have live errors: %v", err)
+		}
+		if catcher.Dropped() != 1 || catcher.Total() != 2 {
+			t.Fatalf("catcher should count expired errors: %d dropped, %d total", catcher.Dropped(), catcher.Total())
+		}
+
+		clock.Advance(5 * time.Minute)
+		if catcher.HasErrors() || catcher.Resolve() != nil {
+			t.Fatal("all errors should expire")
+		}
+	})
+	t.Run("ExpiredErrorsAreNotOmitted", func(t *testing.T) {
+		for name, opts := range map[string][]CatcherOption{
+			"Default":  nil,
+			"Numbered": {WithFormatter(NumberedFormatter())},
+		} {
+			clock := NewFakeClock(start)
+			catcher := MakeTimeWindowCatcher(time.Minute, append(opts, WithClock(clock))...)
+			catcher.New("one")
+			clock.Advance(2 * time.Minute)
+			catcher.New("two")
+
+			if out := catcher.String(); strings.Contains(out, "omitted") || !strings.Contains(out, "two") {
+				t.Errorf("%s: expired errors should not be reported as omitted: %q", name, out)
+			}
+
+			clock.Advance(2 * time.Minute)
+			if catcher.Len() != 0 {
+				t.Fatalf("%s: all errors should expire, has %d", name, catcher.Len())
+			}
+			if catcher.String() != "" || catcher.Error() != "" {
+				t.Errorf("%s: an empty catcher should render as empty: %q", name, catcher.String())
+			}
+			if catcher.Dropped() != 2 || catcher.Total() != 2 {
+				t.Errorf("%s: catcher should count expired errors: %d dropped, %d total", name, catcher.Dropped(), catcher.Total())
+			}
+		}
+	})
+	t.Run("ExpiresOnWrite", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		catcher := MakeTimeWindowCatcher(time.Minute, WithClock(clock)).(*timeAnnotatingCatcher)
+		catcher.New("one")
+		clock.Advance(2 * time.Minute)
+		catcher.New("two")
+
+		if catcher.errs.len() != 1 {
+			t.Fatalf("adding should remove expired errors, has %d", catcher.errs.len())
+		}
+
+		clock.Advance(2 * time.Minute)
+		catcher.Extend([]error{errors.New("three")})
+		if catcher.errs.len() != 1 {
+			t.Fatalf("extending should remove expired errors, has %d", catcher.errs.len())
+		}
+	})
+	t.Run("WindowBoundary", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		catcher := MakeTimeWindowCatcher(time.Minute, WithClock(clock))
+		catcher.New("one")
+		clock.Advance(time.Minute)
+
+		if catcher.Len() != 1 {
+			t.Fatal("errors should not expire at the edge of the window")
+		}
+	})
+	t.Run("PreviouslyAnnotatedErrors", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		catcher := MakeTimeWindowCatcher(time.Minute, WithClock(clock))
+		catcher.Add(&timestampError{err: errors.New("old"), time: start.Add(-time.Hour)})
+		catcher.New("new")
+
+		if catcher.Len() != 1 || !strings.Contains(catcher.String(), "new") {
+			t.Fatalf("errors annotated before the window should expire: %s", catcher)
+		}
+	})
+	t.Run("DrainAndFlush", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		catcher := MakeTimeWindowCatcher(time.Minute, WithClock(clock))
+		catcher.New("one")
+		clock.Advance(2 * time.Minute)
+
+		if catcher.Drain() != nil {
+			t.Fatal("drain should not report expired errors")
+		}
+
+		catcher.New("two")
+		clock.Advance(2 * time.Minute)
+		if len(catcher.Flush()) != 0 {
+			t.Fatal("flush should not report expired errors")
+		}
+	})
+	t.Run("ZeroWindow", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		catcher := MakeTimeWindowCatcher(0, WithClock(clock))
+		catcher.New("one")
+		clock.Advance(time.Hour)
+
+		if catcher.Len() != 1 {
+			t.Fatal("errors should not expire without a window")
+		}
+	})
+}
+
+func TestTimestampClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	t.Run("Catchers", func(t *testing.T) {
+		for name, factory := range map[string]func(...CatcherOption) Catcher{
+			"Timestamp":         NewTimestampCatcher,
+			"ExtendedTimestamp": NewExtendedTimestampCatcher,
+			"Dedup":             NewDedupCatcher,
+		} {
+			t.Run(name, func(t *testing.T) {
+				clock := NewFakeClock(start)
+				clock.SetStep(time.Second)
+
+				catcher := factory(WithClock(clock))
+				catcher.New("one")
+				catcher.New("two")
+				catcher.Extend([]error{errors.New("three")})
+
+				errs := catcher.Errors()
+				if len(errs) != 3 {
+					t.Fatalf("catcher has %d errors", len(errs))
+				}
+				for idx, err := range errs {
+					ts, ok := ErrorTimeFinder(err)
+					if !ok || !ts.Equal(start.Add(time.Duration(idx)*time.Second)) {
+						t.Fatalf("at %d, error has timestamp %s", idx, ts)
+					}
+				}
+				if !strings.Contains(errs[1].Error(), "12:00:01") {
+					t.Fatalf("error should have the timestamp: %s", errs[1])
+				}
+			})
+		}
+	})
+	t.Run("UTC", func(t *testing.T) {
+		catcher := NewTimestampCatcher(WithClock(NewFakeClock(start)), WithUTCTimestamps())
+		catcher.New("one")
+
+		ts, _ := ErrorTimeFinder(catcher.Errors()[0])
+		if ts.Location() != time.UTC || !ts.Equal(start) {
+			t.Fatalf("timestamp should be UTC, not %s", ts)
+		}
+		if err := catcher.Errors()[0]; !strings.Contains(err.Error(), "17:00:00Z") {
+			t.Fatalf("error should have the UTC timestamp: %s", err)
+		}
+	})
+	t.Run("Wrap", func(t *testing.T) {
+		clock := NewFakeClock(start)
+
+		ts, ok := ErrorTimeFinder(WrapErrorTime(errors.New("one"), WithClock(clock)))
+		if !ok || !ts.Equal(start) {
+			t.Fatalf("wrapped error has timestamp %s", ts)
+		}
+
+		err := WrapErrorTimeMessage(errors.New("one"), "context", WithClock(clock), WithUTCTimestamps())
+		ts, ok = ErrorTimeFinder(err)
+		if !ok || !ts.Equal(start) || ts.Location() != time.UTC {
+			t.Fatalf("wrapped error has timestamp %s", ts)
+		}
+		if !strings.HasPrefix(err.Error(), "[2024-01-01T17:00:00Z], context: one") {
+			t.Fatalf("unexpected message %q", err)
+		}
+		if WrapErrorTimeMessage(nil, "context", WithClock(clock)) != nil {
+			t.Fatal("wrapping nil should produce nil")
+		}
+	})
+	t.Run("DefaultsToSystemClock", func(t *testing.T) {
+		before := time.Now()
+		ts, ok := ErrorTimeFinder(WrapErrorTime(errors.New("one")))
+		if !ok || ts.Before(before) || ts.After(time.Now()) {
+			t.Fatalf("timestamp %s should come from the system clock", ts)
+		}
+	})
+}
+
+func TestTimestampLayout(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 123456789, time.UTC)
+	est := time.FixedZone("EST", -5*60*60)
+
+	t.Run("Default", func(t *testing.T) {
+		err := WrapErrorTime(errors.New("hello"), WithClock(NewFakeClock(ts)))
+		if err.Error() != "[2024-01-01T12:00:00Z], hello" {
+			t.Fatalf("unexpected message %q", err)
+		}
+	})
+	t.Run("Verbs", func(t *testing.T) {
+		err := WrapErrorTime(errors.New("hello"),
+			WithClock(NewFakeClock(ts)), WithRFC3339NanoTimestamps(), WithTimestampLocation(est))
+
+		for verb, expected := range map[string]string{
+			"%s": "[2024-01-01T07:00:00.123456789-05:00] hello",
+			"%v": "[2024-01-01T07:00:00.123456789-05:00] hello",
+			"%q": `[2024-01-01T07:00:00.123456789-05:00] "hello"`,
+		} {
+			if out := fmt.Sprintf(verb, err); out != expected {
+				t.Errorf("%s rendered %q, expected %q", verb, out, expected)
+			}
+		}
+		if out := fmt.Sprintf("%+v", err); !strings.HasPrefix(out, "[2024-01-01T07:00:00.123456789-05:00] hello") {
+			t.Errorf("%%+v rendered %q", out)
+		}
+		if err.Error() != "[2024-01-01T07:00:00.123456789-05:00], hello" {
+			t.Errorf("unexpected message %q", err)
+		}
+
+		found, _ := ErrorTimeFinder(err)
+		if found.Location() != time.UTC {
+			t.Error("the location should not change the time of the error")
+		}
+	})
+	t.Run("CustomLayout", func(t *testing.T) {
+		err := WrapErrorTimeMessage(errors.New("hello"), "context",
+			WithClock(NewFakeClock(ts)), WithTimestampLayout(time.Kitchen))
+		if err.Error() != "[12:00PM], context: hello" {
+			t.Fatalf("unexpected message %q", err)
+		}
+	})
+	t.Run("Catchers", func(t *testing.T) {
+		for name, factory := range map[string]func(...CatcherOption) Catcher{
+			"Timestamp":         NewTimestampCatcher,
+			"ExtendedTimestamp": NewExtendedTimestampCatcher,
+			"TimeWindow": func(opts ...CatcherOption) Catcher {
+				return MakeTimeWindowCatcher(time.Hour, opts...)
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				catcher := factory(WithClock(NewFakeClock(ts)), WithRFC3339NanoTimestamps())
+				catcher.New("one")
+				catcher.Extend([]error{errors.New("two")})
+
+				for _, err := range catcher.Errors() {
+					if !strings.Contains(err.Error(), "2024-01-01T12:00:00.123456789Z") {
+						t.Fatalf("error should use the layout: %q", err)
+					}
+				}
+			})
+		}
+	})
+	t.Run("PreviouslyAnnotated", func(t *testing.T) {
+		orig := WrapErrorTime(errors.New("hello"), WithClock(NewFakeClock(ts)))
+
+		catcher := NewTimestampCatcher(WithTimestampLocation(est))
+		catcher.Add(orig)
+
+		if err := catcher.Errors()[0]; !strings.Contains(err.Error(), "07:00:00-05:00") {
+			t.Fatalf("catcher should render annotated errors with its location: %q", err)
+		}
+		if !strings.Contains(orig.Error(), "12:00:00Z") {
+			t.Fatalf("catcher should not modify the annotated error: %q", orig)
+		}
+	})
+	t.Run("WrapNil", func(t *testing.T) {
+		if WrapErrorTime(nil) != nil {
+			t.Fatal("wrapping nil should produce nil")
+		}
+	})
+}
+
+// detailedError renders additional details, as errors with stack
+// traces do, in the %+v format.
+type detailedError struct {
+	msg    string
+	detail string
+}
+
+func (e *detailedError) Error() string { return e.msg }
+func (e *detailedError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "%s\n\t%s", e.msg, e.detail)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = fmt.Fprint(s, e.msg)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.msg)
+	}
+}
@@ -11,16 +11,113 @@
 // buffered independently.
 package emt
 
-import "context"
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
 
 // ErrorChannel provides an error management utility for integration
 // in code that makes use of channels.
 type ErrorChannel struct {
+	// the counters are accessed atomically, and must be first in
+	// the struct to be aligned on 32-bit platforms.
+	counters    overflowCounters
+	subCounters overflowCounters
+
 	errRecv chan error
 	errSend chan error
 	catcher Catcher
+	policy  OverflowPolicy
 	cancel  context.CancelFunc
 	ctx     context.Context
+
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+	outMu     sync.RWMutex
+	outClosed bool
+
+	subMu      sync.RWMutex
+	subs       map[*subscription]struct{}
+	subsClosed chan struct{}
+}
+
+// OverflowPolicy controls what an ErrorChannel does with an error
+// when the Out channel does not have capacity for it. Regardless of
+// the policy, all errors are saved to the ErrorChannel's Catcher.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for the Out channel to have capacity, or
+	// for the ErrorChannel to stop. This is the default.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the error that does not fit in
+	// the Out channel.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest buffered error from
+	// the Out channel to make room for the new error. When the Out
+	// channel is unbuffered there is nothing to discard, and the
+	// policy behaves like OverflowDropNewest.
+	OverflowDropOldest
+	// OverflowCatcherOnly never sends errors to the Out channel.
+	OverflowCatcherOnly
+)
+
+// ErrorChannelStats reports the number of errors that an
+// ErrorChannel has not sent to the Out channel, by cause, and the
+// number of errors that it has not sent to subscribers, across all
+// subscriptions.
+type ErrorChannelStats struct {
+	DroppedNewest int64
+	DroppedOldest int64
+	NotForwarded  int64
+
+	SubscriberDroppedNewest int64
+	SubscriberDroppedOldest int64
+}
+
+// ErrorChannelOption configures an ErrorChannel at construction
+// time. See NewErrorChannelWithOptions.
+type ErrorChannelOption func(*errorChannelOptions)
+
+type errorChannelOptions struct {
+	catcher Catcher
+	policy  OverflowPolicy
+	inSize  int
+	outSize int
+}
+
+// WithCatcher sets the Catcher that the ErrorChannel uses to collect
+// errors. By default an ErrorChannel uses NewCatcher, which stores
+// all errors; use a bounded catcher, such as one from
+// MakeTimestampCatcher, for long running ErrorChannels.
+func WithCatcher(c Catcher) ErrorChannelOption {
+	return func(opts *errorChannelOptions) { opts.catcher = c }
+}
+
+// WithBufferSize sets the size of the buffer for both the input and
+// output channels.
+func WithBufferSize(size int) ErrorChannelOption {
+	return func(opts *errorChannelOptions) { opts.inSize, opts.outSize = size, size }
+}
+
+// WithInBufferSize sets the size of the buffer for the input (In)
+// channel.
+func WithInBufferSize(size int) ErrorChannelOption {
+	return func(opts *errorChannelOptions) { opts.inSize = size }
+}
+
+// WithOutBufferSize sets the size of the buffer for the output (Out)
+// channel.
+func WithOutBufferSize(size int) ErrorChannelOption {
+	return func(opts *errorChannelOptions) { opts.outSize = size }
+}
+
+// WithOverflowPolicy sets the policy that the ErrorChannel uses when
+// the Out channel does not have capacity for an error.
+func WithOverflowPolicy(policy OverflowPolicy) ErrorChannelOption {
+	return func(opts *errorChannelOptions) { opts.policy = policy }
 }
 
 // NewErrorChannel constructs and starts an ErrorChannel instance. The
@@ -29,13 +126,45 @@ type ErrorChannel struct {
 // which can store 64 errors in the channels, although the embedded
 // Catcher will store *all* submitted errors.
 func NewErrorChannel(ctx context.Context, size int) *ErrorChannel {
+	return NewErrorChannelWithOptions(ctx, WithBufferSize(size))
+}
+
+// NewErrorChannelWithOptions constructs and starts an ErrorChannel
+// instance configured by the options. Without options, the channels
+// are unbuffered and the ErrorChannel uses the catcher returned by
+// NewCatcher. Negative buffer sizes are treated as zero.
+func NewErrorChannelWithOptions(ctx context.Context, opts ...ErrorChannelOption) *ErrorChannel {
+	conf := &errorChannelOptions{}
+	for _, opt := range opts {
+		opt(conf)
+	}
+
+	if conf.catcher == nil {
+		conf.catcher = NewCatcher()
+	}
+	if conf.inSize < 0 {
+		conf.inSize = 0
+	}
+	if conf.outSize < 0 {
+		conf.outSize = 0
+	}
+
 	ec := &ErrorChannel{
-		errRecv: make(chan error, size),
-		errSend: make(chan error, size),
-		catcher: NewCatcher(),
+		errRecv: make(chan error, conf.inSize),
+		errSend: make(chan error, conf.outSize),
+		catcher: conf.catcher,
+		policy:  conf.policy,
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+
+		subs:       map[*subscription]struct{}{},
+		subsClosed: make(chan struct{}),
 	}
 	ec.ctx, ec.cancel = context.WithCancel(ctx)
-	go ec.start(ec.ctx)
+	go func() {
+		defer close(ec.done)
+		ec.start(ec.ctx)
+	}()
 
 	return ec
 }
@@ -51,54 +180,212 @@ func (ec *ErrorChannel) start(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
+			select {
+			case <-ec.closing:
+				// Shutdown's context expired: save the
+				// buffered errors rather than discard them.
+				ec.drain(ctx)
+			default:
+			}
+			return
+		case <-ec.closing:
+			ec.drain(ctx)
 			return
 		case err := <-ec.errRecv:
 			if err == nil {
 				continue
 			}
-			ec.catcher.Add(err)
+			ec.process(ctx, err)
+		}
+	}
+
+}
+
+// drain processes the errors buffered in the In channel when the
+// ErrorChannel closes, and then closes the Out channel. Errors sent
+// to the In channel after drain begins are not processed.
+func (ec *ErrorChannel) drain(ctx context.Context) {
+	// the processor is the only reader of the In channel, so these
+	// receives never block.
+	for count := len(ec.errRecv); count > 0; count-- {
+		err := <-ec.errRecv
+		if err == nil {
+			continue
+		}
+		ec.process(ctx, err)
+	}
+
+	ec.closeOut()
+}
+
+func (ec *ErrorChannel) closeOut() {
+	ec.outMu.Lock()
+	defer ec.outMu.Unlock()
+
+	if ec.outClosed {
+		return
+	}
+
+	ec.outClosed = true
+	close(ec.errSend)
+	ec.closeSubscriptions()
+}
+
+// process saves the error in the Catcher and sends it to the Out
+// channel and to all subscribers.
+func (ec *ErrorChannel) process(ctx context.Context, err error) {
+	ec.catcher.Add(err)
+	ec.forward(ctx, err)
+	ec.publish(err)
+}
+
+// forward sends the error to the Out channel according to the
+// overflow policy. Once the Out channel is closed, errors are not
+// forwarded.
+func (ec *ErrorChannel) forward(ctx context.Context, err error) {
+	ec.outMu.RLock()
+	defer ec.outMu.RUnlock()
+
+	if ec.outClosed {
+		return
+	}
+
+	deliver(ctx, ec.ctx.Done(), ec.errSend, ec.policy, err, &ec.counters)
+}
+
+// overflowCounters records the errors that deliver does not send. The
+// fields are accessed atomically.
+type overflowCounters struct {
+	droppedNewest int64
+	droppedOldest int64
+	notForwarded  int64
+}
+
+// deliver sends the error on the channel according to the overflow
+// policy. With the blocking policy, deliver returns without sending
+// when either the context is canceled or the abort channel is
+// closed.
+func deliver(ctx context.Context, abort <-chan struct{}, ch chan error, policy OverflowPolicy, err error, counters *overflowCounters) {
+	switch policy {
+	case OverflowCatcherOnly:
+		atomic.AddInt64(&counters.notForwarded, 1)
+	case OverflowDropNewest:
+		select {
+		case ch <- err:
+		default:
+			atomic.AddInt64(&counters.droppedNewest, 1)
+		}
+	case OverflowDropOldest:
+		for {
 			select {
-			case <-ctx.Done():
+			case ch <- err:
 				return
-			case ec.errSend <- err:
+			default:
+			}
+
+			select {
+			case <-ch:
+				atomic.AddInt64(&counters.droppedOldest, 1)
+			default:
+				if cap(ch) == 0 {
+					atomic.AddInt64(&counters.droppedNewest, 1)
+					return
+				}
 			}
 		}
+	default:
+		select {
+		case <-ctx.Done():
+		case <-abort:
+		case ch <- err:
+		}
 	}
-
 }
 
 // Stop aborts the background process that handles errors, and will
 // cause the Wait method to return the resolved errors collected by
-// the object over it's lifetime.
+// the object over it's lifetime. Errors buffered in the In channel
+// are discarded, and the Out channel is not closed, but the channels
+// of subscribers are.
 func (ec *ErrorChannel) Stop() { ec.cancel() }
 
+// Close gracefully shuts down the ErrorChannel, as Shutdown, without
+// a deadline. With the OverflowBlock policy, Close does not return
+// until the errors buffered in the In channel are consumed from the
+// Out channel. Close always returns nil, and satisfies io.Closer.
+func (ec *ErrorChannel) Close() error { return ec.Shutdown(context.Background()) }
+
+// Shutdown stops the ErrorChannel from processing new errors from
+// the In channel, processes the errors already buffered in the In
+// channel, saving them in the Catcher and sending them to the Out
+// channel, and then closes the Out channel so that consumers ranging
+// over Out return. When Shutdown returns, the ErrorChannel is
+// stopped, and Wait returns the resolved error.
+//
+// If the context is canceled before the buffered errors are
+// processed, Shutdown aborts as Stop, saves the remaining buffered
+// errors in the Catcher without sending them to Out, closes the Out
+// channel, and returns the context's error. Calling Shutdown after
+// Stop closes the Out channel.
+func (ec *ErrorChannel) Shutdown(ctx context.Context) error {
+	ec.closeOnce.Do(func() { close(ec.closing) })
+
+	var err error
+	select {
+	case <-ec.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+		ec.cancel()
+		<-ec.done
+	}
+
+	ec.cancel()
+	ec.closeOut()
+
+	return err
+}
+
 // Resolve returns an aggregated error observed by the ErrorChannel.
 func (ec *ErrorChannel) Resolve() error { return ec.catcher.Resolve() }
 
 // In returns a channel that you can use to submit errors to the
-// collector. This channel is never closed.
+// collector. This channel is never closed; however, once the
+// ErrorChannel is stopped or closed, errors sent to it are not
+// processed.
 func (ec *ErrorChannel) In() chan<- error { return ec.errRecv }
 
-// In returns a channel that you can use to consume errors from the
-// error channel. This channel is never closed.
+// Out returns a channel that you can use to consume errors from the
+// error channel. This channel is closed by Close or Shutdown, but not
+// by Stop. Each error is received by only one consumer of Out; use
+// Subscribe to deliver every error to several consumers.
 func (ec *ErrorChannel) Out() <-chan error { return ec.errSend }
 
 // Collect saves the error in question in the underlying Catcher and
-// then sends the error to the OUT channel, blocking until that
-// channel has capacity, Stop is called or the context is canceled. If
-// the context is canceled or Stop is called, the error may not be
+// then sends the error to the OUT channel according to the overflow
+// policy. With the default policy, Collect blocks until that channel
+// has capacity, Stop is called or the context is canceled. If the
+// context is canceled or Stop is called, the error may not be
 // propogated to the Out channel.
 //
 // All errors are saved to the catcher regardless of the state of the
 // context, ErrorChannel's background thread or the OUT channel.
 func (ec *ErrorChannel) Collect(ctx context.Context, err error) {
 	if err != nil {
-		ec.catcher.Add(err)
-		select {
-		case <-ctx.Done():
-		case <-ec.ctx.Done():
-		case ec.errSend <- err:
-		}
+		ec.process(ctx, err)
+	}
+}
+
+// Stats returns the number of errors that the ErrorChannel has not
+// sent to the Out channel and to subscribers because of their
+// overflow policies.
+func (ec *ErrorChannel) Stats() ErrorChannelStats {
+	return ErrorChannelStats{
+		DroppedNewest: atomic.LoadInt64(&ec.counters.droppedNewest),
+		DroppedOldest: atomic.LoadInt64(&ec.counters.droppedOldest),
+		NotForwarded:  atomic.LoadInt64(&ec.counters.notForwarded),
+
+		SubscriberDroppedNewest: atomic.LoadInt64(&ec.subCounters.droppedNewest),
+		SubscriberDroppedOldest: atomic.LoadInt64(&ec.subCounters.droppedOldest),
 	}
 }
 
@@ -0,0 +1,104 @@
+package emt
+
+import "context"
+
+// subscription delivers errors from an ErrorChannel to a single
+// subscriber.
+type subscription struct {
+	ctx    context.Context
+	ch     chan error
+	policy OverflowPolicy
+}
+
+// Subscribe returns a channel that receives every error that the
+// ErrorChannel processes after the call to Subscribe, independently
+// of the Out channel and of other subscribers. The size controls the
+// buffer of the channel. When the subscriber does not keep up, the
+// ErrorChannel's overflow policy applies, as with the Out channel;
+// use SubscribeWithPolicy to select a policy for the subscription.
+//
+// The channel is closed when the context is canceled, when the
+// ErrorChannel is closed with Close or Shutdown, or when it stops,
+// with Stop or because its own context is canceled.
+func (ec *ErrorChannel) Subscribe(ctx context.Context, size int) <-chan error {
+	return ec.SubscribeWithPolicy(ctx, size, ec.policy)
+}
+
+// SubscribeWithPolicy returns a channel that receives every error,
+// as Subscribe, and uses the overflow policy when the subscriber
+// does not keep up. With OverflowBlock, a slow subscriber delays the
+// processing of errors until it receives them or its context is
+// canceled. OverflowCatcherOnly is not meaningful for a subscription,
+// and is treated as OverflowDropNewest.
+func (ec *ErrorChannel) SubscribeWithPolicy(ctx context.Context, size int, policy OverflowPolicy) <-chan error {
+	if size < 0 {
+		size = 0
+	}
+	if policy == OverflowCatcherOnly {
+		policy = OverflowDropNewest
+	}
+
+	sub := &subscription{
+		ctx:    ctx,
+		ch:     make(chan error, size),
+		policy: policy,
+	}
+
+	ec.subMu.Lock()
+	defer ec.subMu.Unlock()
+
+	select {
+	case <-ec.subsClosed:
+		close(sub.ch)
+		return sub.ch
+	default:
+	}
+
+	ec.subs[sub] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			ec.unsubscribe(sub)
+		case <-ec.ctx.Done():
+			ec.unsubscribe(sub)
+		case <-ec.subsClosed:
+		}
+	}()
+
+	return sub.ch
+}
+
+func (ec *ErrorChannel) unsubscribe(sub *subscription) {
+	ec.subMu.Lock()
+	defer ec.subMu.Unlock()
+
+	if _, ok := ec.subs[sub]; !ok {
+		return
+	}
+
+	delete(ec.subs, sub)
+	close(sub.ch)
+}
+
+func (ec *ErrorChannel) closeSubscriptions() {
+	ec.subMu.Lock()
+	defer ec.subMu.Unlock()
+
+	close(ec.subsClosed)
+	for sub := range ec.subs {
+		delete(ec.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// publish sends the error to all subscribers, according to their
+// overflow policies.
+func (ec *ErrorChannel) publish(err error) {
+	ec.subMu.RLock()
+	defer ec.subMu.RUnlock()
+
+	for sub := range ec.subs {
+		deliver(sub.ctx, ec.ctx.Done(), sub.ch, sub.policy, err, &ec.subCounters)
+	}
+}
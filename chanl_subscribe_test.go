@@ -0,0 +1,169 @@
+package emt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestErrorChannelSubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("FanOut", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithOverflowPolicy(OverflowCatcherOnly))
+		defer ec.Stop()
+
+		subs := []<-chan error{
+			ec.SubscribeWithPolicy(ctx, 0, OverflowBlock),
+			ec.SubscribeWithPolicy(ctx, 4, OverflowBlock),
+			ec.SubscribeWithPolicy(ctx, 1, OverflowBlock),
+		}
+
+		wg := &sync.WaitGroup{}
+		counts := make([]int, len(subs))
+		for idx, sub := range subs {
+			wg.Add(1)
+			go func(idx int, sub <-chan error) {
+				defer wg.Done()
+				for range sub {
+					counts[idx]++
+				}
+			}(idx, sub)
+		}
+
+		for i := 0; i < 10; i++ {
+			ec.In() <- fmt.Errorf("err %d", i)
+		}
+		if err := ec.Close(); err != nil {
+			t.Fatal(err)
+		}
+		wg.Wait()
+
+		for idx, count := range counts {
+			if count != 10 {
+				t.Fatalf("subscriber %d received %d errors", idx, count)
+			}
+		}
+	})
+	t.Run("CancelEndsSubscription", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithOverflowPolicy(OverflowCatcherOnly))
+		defer ec.Stop()
+
+		sctx, scancel := context.WithCancel(ctx)
+		sub := ec.Subscribe(sctx, 1)
+		other := ec.Subscribe(ctx, 1)
+		scancel()
+
+		select {
+		case _, ok := <-sub:
+			if ok {
+				t.Fatal("subscription should not receive errors")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("subscription should be closed")
+		}
+
+		ec.Collect(ctx, errors.New("after"))
+		if err := <-other; err == nil || err.Error() != "after" {
+			t.Fatalf("other subscriptions should receive errors: %v", err)
+		}
+	})
+	t.Run("CancelUnblocksSlowSubscriber", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithOverflowPolicy(OverflowCatcherOnly))
+		defer ec.Stop()
+
+		sctx, scancel := context.WithCancel(ctx)
+		_ = ec.SubscribeWithPolicy(sctx, 0, OverflowBlock)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			ec.Collect(ctx, errors.New("blocked"))
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("blocking subscriber should hold the error")
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		scancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("canceling the subscription should release the error")
+		}
+	})
+	t.Run("SlowConsumerPolicies", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithOverflowPolicy(OverflowCatcherOnly))
+		defer ec.Stop()
+
+		newest := ec.SubscribeWithPolicy(ctx, 2, OverflowDropNewest)
+		oldest := ec.SubscribeWithPolicy(ctx, 2, OverflowDropOldest)
+		for i := 0; i < 5; i++ {
+			ec.Collect(ctx, fmt.Errorf("err %d", i))
+		}
+
+		if a, b := <-newest, <-newest; a.Error() != "err 0" || b.Error() != "err 1" {
+			t.Fatalf("drop-newest subscriber received %v and %v", a, b)
+		}
+		if a, b := <-oldest, <-oldest; a.Error() != "err 3" || b.Error() != "err 4" {
+			t.Fatalf("drop-oldest subscriber received %v and %v", a, b)
+		}
+
+		stats := ec.Stats()
+		if stats.SubscriberDroppedNewest != 3 || stats.SubscriberDroppedOldest != 3 {
+			t.Fatalf("unexpected stats %+v", stats)
+		}
+	})
+	t.Run("DefaultsToChannelPolicy", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithOverflowPolicy(OverflowDropNewest))
+		defer ec.Stop()
+
+		sub := ec.Subscribe(ctx, 1)
+		ec.Collect(ctx, errors.New("one"))
+		ec.Collect(ctx, errors.New("two"))
+
+		if err := <-sub; err.Error() != "one" {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if stats := ec.Stats(); stats.SubscriberDroppedNewest != 1 {
+			t.Fatalf("unexpected stats %+v", stats)
+		}
+	})
+	t.Run("StopEndsSubscription", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx)
+		sub := ec.Subscribe(ctx, 1)
+		ec.Stop()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range sub {
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("subscription should be closed after Stop")
+		}
+
+		if _, ok := <-ec.Subscribe(ctx, 1); ok {
+			t.Fatal("subscriptions after Stop should be closed")
+		}
+	})
+	t.Run("SubscribeAfterClose", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx)
+		if err := ec.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := <-ec.Subscribe(ctx, 1); ok {
+			t.Fatal("subscription should be closed")
+		}
+	})
+}
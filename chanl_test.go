@@ -20,6 +20,34 @@ func TestChannel(t *testing.T) {
 				return NewErrorChannel(ctx, size)
 			},
 		},
+		{
+			Name: "Options",
+			Factory: func(ctx context.Context, size int) *ErrorChannel {
+				return NewErrorChannelWithOptions(ctx, WithBufferSize(size))
+			},
+		},
+		{
+			Name: "TimestampCatcher",
+			Factory: func(ctx context.Context, size int) *ErrorChannel {
+				return NewErrorChannelWithOptions(ctx,
+					WithCatcher(MakeTimestampCatcher(1024)),
+					WithInBufferSize(size),
+					WithOutBufferSize(size),
+				)
+			},
+		},
+		{
+			Name: "DropNewest",
+			Factory: func(ctx context.Context, size int) *ErrorChannel {
+				return NewErrorChannelWithOptions(ctx, WithBufferSize(size), WithOverflowPolicy(OverflowDropNewest))
+			},
+		},
+		{
+			Name: "DropOldest",
+			Factory: func(ctx context.Context, size int) *ErrorChannel {
+				return NewErrorChannelWithOptions(ctx, WithBufferSize(size), WithOverflowPolicy(OverflowDropOldest))
+			},
+		},
 	}
 
 	cases := []struct {
@@ -129,14 +157,22 @@ func TestChannel(t *testing.T) {
 		{
 			Name: "NoPropogationAfterStop",
 			Test: func(ctx context.Context, t *testing.T, ec *ErrorChannel, size int) {
+				send := ec.In()
+				ec.Stop()
+				time.Sleep(time.Millisecond)
+
+				// start the timeout after the sleep, which may
+				// take longer than the timeout, and wait for the
+				// sender so that it never reports after the test
+				// returns.
 				var cancel context.CancelFunc
 				ctx, cancel = context.WithTimeout(ctx, 4*time.Millisecond)
 				defer cancel()
 
-				send := ec.In()
-				ec.Stop()
-				time.Sleep(time.Millisecond)
+				sent := make(chan struct{})
+				defer func() { <-sent }()
 				go func() {
+					defer close(sent)
 					count := 0
 					for {
 						select {
@@ -221,3 +257,280 @@ func TestChannel(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorChannelOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("Defaults", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx)
+		defer ec.Stop()
+		if cap(ec.errRecv) != 0 || cap(ec.errSend) != 0 {
+			t.Fatalf("channels should be unbuffered, in=%d out=%d", cap(ec.errRecv), cap(ec.errSend))
+		}
+		if ec.catcher == nil {
+			t.Fatal("error channel should have a default catcher")
+		}
+	})
+	t.Run("SeparateBufferSizes", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithInBufferSize(4), WithOutBufferSize(16))
+		defer ec.Stop()
+		if cap(ec.errRecv) != 4 || cap(ec.errSend) != 16 {
+			t.Fatalf("unexpected buffer sizes, in=%d out=%d", cap(ec.errRecv), cap(ec.errSend))
+		}
+	})
+	t.Run("NegativeBufferSizes", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithBufferSize(-1))
+		defer ec.Stop()
+		if cap(ec.errRecv) != 0 || cap(ec.errSend) != 0 {
+			t.Fatalf("channels should be unbuffered, in=%d out=%d", cap(ec.errRecv), cap(ec.errSend))
+		}
+	})
+	t.Run("CallerCatcher", func(t *testing.T) {
+		catcher := MakeTimestampCatcher(2)
+		ec := NewErrorChannelWithOptions(ctx, WithCatcher(catcher), WithOutBufferSize(8))
+		defer ec.Stop()
+
+		for i := 0; i < 4; i++ {
+			ec.Collect(ctx, fmt.Errorf("err %d", i))
+		}
+
+		if catcher.Len() != 2 || catcher.Dropped() != 2 {
+			t.Fatalf("bounded catcher should hold two errors, len=%d dropped=%d", catcher.Len(), catcher.Dropped())
+		}
+		if _, ok := ErrorTimeFinder(catcher.Errors()[0]); !ok {
+			t.Fatal("errors should be annotated with timestamps")
+		}
+		if err := ec.Resolve(); err == nil || !strings.Contains(err.Error(), "err 3") {
+			t.Fatalf("error channel should resolve from the catcher: %v", err)
+		}
+	})
+}
+
+func TestErrorChannelOverflow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	collect := func(ec *ErrorChannel, count int) {
+		for i := 0; i < count; i++ {
+			ec.Collect(ctx, fmt.Errorf("err %d", i))
+		}
+	}
+	drain := func(ec *ErrorChannel) []string {
+		var out []string
+		for {
+			select {
+			case err := <-ec.Out():
+				out = append(out, err.Error())
+			default:
+				return out
+			}
+		}
+	}
+
+	t.Run("BlockIsDefault", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithOutBufferSize(2))
+		collect(ec, 2)
+
+		sctx, scancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer scancel()
+		ec.Collect(sctx, errors.New("blocked"))
+		if sctx.Err() == nil {
+			t.Fatal("collect should block until the context expires")
+		}
+		if stats := ec.Stats(); stats != (ErrorChannelStats{}) {
+			t.Fatalf("blocking policy should not count drops: %+v", stats)
+		}
+		ec.Stop()
+	})
+	t.Run("DropNewest", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithOutBufferSize(2), WithOverflowPolicy(OverflowDropNewest))
+		defer ec.Stop()
+		collect(ec, 5)
+
+		if out := drain(ec); len(out) != 2 || out[0] != "err 0" || out[1] != "err 1" {
+			t.Fatalf("unexpected output: %v", out)
+		}
+		if stats := ec.Stats(); stats.DroppedNewest != 3 || stats.DroppedOldest != 0 {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+		if ec.catcher.Len() != 5 {
+			t.Fatal("catcher should collect all errors")
+		}
+	})
+	t.Run("DropOldest", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithOutBufferSize(2), WithOverflowPolicy(OverflowDropOldest))
+		defer ec.Stop()
+		collect(ec, 5)
+
+		if out := drain(ec); len(out) != 2 || out[0] != "err 3" || out[1] != "err 4" {
+			t.Fatalf("unexpected output: %v", out)
+		}
+		if stats := ec.Stats(); stats.DroppedOldest != 3 || stats.DroppedNewest != 0 {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+	})
+	t.Run("DropOldestUnbuffered", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithOverflowPolicy(OverflowDropOldest))
+		defer ec.Stop()
+		collect(ec, 3)
+
+		if stats := ec.Stats(); stats.DroppedNewest != 3 {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+	})
+	t.Run("CatcherOnly", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithBufferSize(2), WithOverflowPolicy(OverflowCatcherOnly))
+		defer ec.Stop()
+		collect(ec, 3)
+		for i := 0; i < 3; i++ {
+			ec.In() <- errors.New("in")
+		}
+
+		if out := drain(ec); len(out) != 0 {
+			t.Fatalf("no errors should be forwarded: %v", out)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for ec.Stats().NotForwarded != 6 {
+			if time.Now().After(deadline) {
+				t.Fatalf("unexpected stats: %+v", ec.Stats())
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if ec.catcher.Len() != 6 {
+			t.Fatal("catcher should collect all errors")
+		}
+	})
+	t.Run("ProducersDoNotStall", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithBufferSize(1), WithOverflowPolicy(OverflowDropNewest))
+		defer ec.Stop()
+
+		sctx, scancel := context.WithTimeout(ctx, time.Second)
+		defer scancel()
+		for i := 0; i < 100; i++ {
+			select {
+			case ec.In() <- errors.New("in"):
+			case <-sctx.Done():
+				t.Fatalf("producer stalled after %d errors", i)
+			}
+		}
+	})
+}
+
+func TestErrorChannelClose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("RangeOverOut", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithBufferSize(8))
+		for i := 0; i < 4; i++ {
+			ec.Collect(ctx, fmt.Errorf("err %d", i))
+		}
+		if err := ec.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		count := 0
+		for range ec.Out() {
+			count++
+		}
+		if count != 4 {
+			t.Fatalf("consumed %d errors", count)
+		}
+		if err := ec.Wait(ctx); err == nil || !strings.Contains(err.Error(), "err 3") {
+			t.Fatalf("wait should return collected errors after close: %v", err)
+		}
+	})
+	t.Run("DrainsBufferedInput", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithInBufferSize(8), WithOutBufferSize(16))
+		// hold the processor so that errors accumulate in the buffer.
+		ec.outMu.Lock()
+		ec.In() <- errors.New("first")
+		for i := 0; i < 8; i++ {
+			ec.In() <- fmt.Errorf("buffered %d", i)
+		}
+		ec.outMu.Unlock()
+
+		var out []string
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for err := range ec.Out() {
+				out = append(out, err.Error())
+			}
+		}()
+
+		if err := ec.Close(); err != nil {
+			t.Fatal(err)
+		}
+		<-done
+
+		if len(out) != 9 || ec.catcher.Len() != 9 {
+			t.Fatalf("buffered errors should be processed, out=%d catcher=%d", len(out), ec.catcher.Len())
+		}
+	})
+	t.Run("CollectAfterClose", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithBufferSize(2))
+		if err := ec.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		ec.Collect(ctx, errors.New("late"))
+		if !ec.catcher.HasErrors() {
+			t.Fatal("collect should save errors after close")
+		}
+		if _, ok := <-ec.Out(); ok {
+			t.Fatal("out channel should be closed and empty")
+		}
+	})
+	t.Run("CloseIsIdempotent", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx)
+		for i := 0; i < 3; i++ {
+			if err := ec.Close(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	t.Run("ShutdownDeadline", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx, WithInBufferSize(4))
+		ec.outMu.Lock()
+		ec.In() <- errors.New("first")
+		for i := 0; i < 4; i++ {
+			ec.In() <- fmt.Errorf("buffered %d", i)
+		}
+		ec.outMu.Unlock()
+
+		sctx, scancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer scancel()
+
+		// nothing consumes from Out, so the blocking policy
+		// cannot forward the buffered errors.
+		if err := ec.Shutdown(sctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("shutdown should report the deadline: %v", err)
+		}
+		if ec.catcher.Len() != 5 {
+			t.Fatalf("buffered errors should be saved, catcher has %d", ec.catcher.Len())
+		}
+		if _, ok := <-ec.Out(); ok {
+			t.Fatal("out channel should be closed")
+		}
+	})
+	t.Run("StopDoesNotCloseOut", func(t *testing.T) {
+		ec := NewErrorChannelWithOptions(ctx)
+		ec.Stop()
+
+		select {
+		case <-ec.Out():
+			t.Fatal("stop should not close the out channel")
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		if err := ec.Shutdown(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := <-ec.Out(); ok {
+			t.Fatal("shutdown after stop should close the out channel")
+		}
+	})
+}
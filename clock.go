@@ -0,0 +1,70 @@
+package emt
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of the current time for catchers and functions
+// that annotate errors with timestamps. Use the WithClock option to
+// provide a clock.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// FakeClock is a Clock that reports a time that changes only when
+// set or advanced, to make timestamps deterministic in tests. Each
+// call to Now advances the clock by the step, if any, so that
+// successive timestamps are distinct. FakeClock is safe for
+// concurrent use.
+type FakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// NewFakeClock constructs a FakeClock that reports the time passed.
+func NewFakeClock(now time.Time) *FakeClock { return &FakeClock{now: now} }
+
+// Now returns the current time of the clock, and then advances the
+// clock by the step.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now
+	c.now = c.now.Add(c.step)
+
+	return now
+}
+
+// Set changes the current time of the clock.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+// Advance moves the current time of the clock forward by the
+// duration.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// SetStep sets the duration that the clock advances after each call
+// to Now. A step of zero, the default, means that Now always reports
+// the same time until the clock is set or advanced.
+func (c *FakeClock) SetStep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.step = d
+}
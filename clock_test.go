@@ -0,0 +1,38 @@
+package emt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Fixed", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		if !clock.Now().Equal(start) || !clock.Now().Equal(start) {
+			t.Fatal("clock should not advance without a step")
+		}
+	})
+	t.Run("SetAndAdvance", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		clock.Advance(time.Hour)
+		if !clock.Now().Equal(start.Add(time.Hour)) {
+			t.Fatal("clock should advance")
+		}
+
+		clock.Set(start)
+		if !clock.Now().Equal(start) {
+			t.Fatal("clock should be set")
+		}
+	})
+	t.Run("Step", func(t *testing.T) {
+		clock := NewFakeClock(start)
+		clock.SetStep(time.Second)
+		for i := 0; i < 3; i++ {
+			if now := clock.Now(); !now.Equal(start.Add(time.Duration(i) * time.Second)) {
+				t.Fatalf("at %d, clock reported %s", i, now)
+			}
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package emt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// errorJSON is the JSON representation of a single error: the
+// message and Go type of the error, the time the error was
+// collected, if known, the fields of the error and the code that
+// added it to a catcher, if any, and the chain of errors that it
+// wraps.
+type errorJSON struct {
+	Message   string                 `json:"message"`
+	Timestamp *time.Time             `json:"timestamp,omitempty"`
+	Type      string                 `json:"type"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Caller    *Caller                `json:"caller,omitempty"`
+	Causes    []causeJSON            `json:"causes,omitempty"`
+}
+
+type causeJSON struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// decodedError is an error reconstructed from its JSON form. It
+// reports the message and type of the original error, and wraps the
+// reconstructed cause chain.
+type decodedError struct {
+	msg   string
+	typ   string
+	cause error
+}
+
+func (e *decodedError) Error() string { return e.msg }
+func (e *decodedError) Unwrap() error { return e.cause }
+func (e *decodedError) Cause() error  { return e.cause }
+
+func errorTypeName(err error) string {
+	if derr, ok := err.(*decodedError); ok {
+		return derr.typ
+	}
+
+	return fmt.Sprintf("%T", err)
+}
+
+func unwrapOnce(err error) error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	case interface{ Cause() error }:
+		return e.Cause()
+	default:
+		return nil
+	}
+}
+
+// unwrapAnnotation returns the error that an annotation of this
+// package wraps, and false for other errors, which the JSON form
+// reports.
+func unwrapAnnotation(err error) (error, bool) {
+	switch e := err.(type) {
+	case *timestampError:
+		if e != nil && e.err != nil {
+			return e.err, true
+		}
+	case *fieldError:
+		return e.err, true
+	case *stackError:
+		return e.err, true
+	case *callerError:
+		return e.err, true
+	}
+
+	return err, false
+}
+
+func makeErrorJSON(err error) errorJSON {
+	out := errorJSON{}
+
+	if ts, ok := ErrorTimeFinder(err); ok {
+		out.Timestamp = &ts
+	}
+
+	if fields, ok := ErrorFieldsFinder(err); ok {
+		out.Fields = fields
+	}
+
+	if caller, ok := ErrorCallerFinder(err); ok {
+		out.Caller = &caller
+	}
+
+	// report the annotated error, rather than the annotations.
+	for inner, ok := unwrapAnnotation(err); ok; inner, ok = unwrapAnnotation(err) {
+		err = inner
+	}
+
+	out.Message = err.Error()
+	out.Type = errorTypeName(err)
+
+	for cause := unwrapOnce(err); cause != nil; cause = unwrapOnce(cause) {
+		if _, ok := unwrapAnnotation(cause); ok {
+			continue
+		}
+		out.Causes = append(out.Causes, causeJSON{Message: cause.Error(), Type: errorTypeName(cause)})
+	}
+
+	return out
+}
+
+func (e errorJSON) resolve() error {
+	var cause error
+	for idx := len(e.Causes) - 1; idx >= 0; idx-- {
+		cause = &decodedError{msg: e.Causes[idx].Message, typ: e.Causes[idx].Type, cause: cause}
+	}
+
+	var err error = &decodedError{msg: e.Message, typ: e.Type, cause: cause}
+	if len(e.Fields) > 0 {
+		err = &fieldError{err: err, fields: e.Fields}
+	}
+	if e.Caller != nil {
+		err = &callerError{err: err, caller: *e.Caller}
+	}
+	if e.Timestamp != nil {
+		err = &timestampError{err: err, time: *e.Timestamp}
+	}
+
+	return err
+}
+
+// MarshalJSON renders the error as a JSON object with the message,
+// timestamp, Go type, fields and cause chain of the annotated error.
+func (e *timestampError) MarshalJSON() ([]byte, error) {
+	if e.err == nil {
+		return nil, errors.New("cannot marshal empty timestamp error")
+	}
+
+	return json.Marshal(makeErrorJSON(e))
+}
+
+// UnmarshalJSON reconstructs a timestamp error from the output of
+// MarshalJSON. The annotated error and its causes report the
+// original messages and types, but are not of the original types.
+func (e *timestampError) UnmarshalJSON(data []byte) error {
+	out := errorJSON{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+
+	ts := out.Timestamp
+	out.Timestamp = nil
+
+	e.err = out.resolve()
+	if ts != nil {
+		e.time = *ts
+	}
+
+	return nil
+}
+
+// MarshalJSON renders the aggregate as a JSON array, with an object
+// for each constituent error that holds the message, the collection
+// timestamp (when known), the Go type, the fields (if any) and the
+// cause chain of the error.
+func (e *AggregateError) MarshalJSON() ([]byte, error) {
+	out := make([]errorJSON, len(e.errs))
+	for idx, err := range e.errs {
+		out[idx] = makeErrorJSON(err)
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reconstructs an aggregate from the output of
+// MarshalJSON, for instance to return errors collected in one
+// process to another. The constituent errors report the original
+// messages, types, timestamps, fields and cause chains, and are
+// visible to ErrorTimeFinder and ErrorFieldsFinder, but errors.Is
+// and errors.As cannot match the original sentinel values or types.
+func (e *AggregateError) UnmarshalJSON(data []byte) error {
+	var in []errorJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	e.errs = make([]error, len(in))
+	for idx := range in {
+		e.errs[idx] = in[idx].resolve()
+	}
+	e.dropped = 0
+	e.render = renderTimestamp
+
+	return nil
+}
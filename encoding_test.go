@@ -0,0 +1,164 @@
+package emt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSON(t *testing.T) {
+	t.Run("TimestampError", func(t *testing.T) {
+		ts := time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC)
+		err := &timestampError{err: fmt.Errorf("outer: %w", fs.ErrNotExist), time: ts}
+
+		data, jerr := json.Marshal(err)
+		if jerr != nil {
+			t.Fatal(jerr)
+		}
+
+		out := map[string]interface{}{}
+		if jerr := json.Unmarshal(data, &out); jerr != nil {
+			t.Fatal(jerr)
+		}
+		if out["message"] != "outer: file does not exist" {
+			t.Fatalf("unexpected message in %s", data)
+		}
+		if out["type"] != "*fmt.wrapError" {
+			t.Fatalf("unexpected type in %s", data)
+		}
+		if out["timestamp"] != ts.Format(time.RFC3339Nano) {
+			t.Fatalf("unexpected timestamp in %s", data)
+		}
+		causes, ok := out["causes"].([]interface{})
+		if !ok || len(causes) != 1 {
+			t.Fatalf("unexpected causes in %s", data)
+		}
+		if cause := causes[0].(map[string]interface{}); cause["message"] != "file does not exist" || cause["type"] != "*errors.errorString" {
+			t.Fatalf("unexpected cause in %s", data)
+		}
+	})
+	t.Run("EmptyTimestampError", func(t *testing.T) {
+		if _, err := json.Marshal(&timestampError{}); err == nil {
+			t.Fatal("empty timestamp errors should not marshal")
+		}
+	})
+	t.Run("TimestampRoundTrip", func(t *testing.T) {
+		ts := time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC)
+		orig := &timestampError{err: fmt.Errorf("outer: %w", errors.New("inner")), time: ts}
+		data, err := json.Marshal(orig)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded := &timestampError{}
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.time.Equal(ts) || decoded.String() != orig.String() {
+			t.Fatalf("round trip produced %v", decoded)
+		}
+
+		again, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again) != string(data) {
+			t.Fatalf("round trip changed json: %s != %s", again, data)
+		}
+	})
+	t.Run("Aggregate", func(t *testing.T) {
+		for _, factory := range []func(...CatcherOption) Catcher{NewBasicCatcher, NewTimestampCatcher, NewDedupCatcher} {
+			catcher := factory()
+			catcher.New("one")
+			catcher.Add(fmt.Errorf("two: %w", fs.ErrPermission))
+
+			data, err := json.Marshal(catcher.Resolve())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out []map[string]interface{}
+			if err := json.Unmarshal(data, &out); err != nil {
+				t.Fatal(err)
+			}
+			if len(out) != 2 {
+				t.Fatalf("%T produced %s", catcher, data)
+			}
+			if !strings.HasPrefix(out[0]["message"].(string), "one") {
+				t.Fatalf("%T produced %s", catcher, data)
+			}
+		}
+	})
+	t.Run("AggregateRoundTrip", func(t *testing.T) {
+		catcher := NewTimestampCatcher()
+		catcher.New("one")
+		catcher.Add(fmt.Errorf("two: %w", fs.ErrPermission))
+		orig := catcher.Resolve()
+
+		data, err := json.Marshal(orig)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded := &AggregateError{}
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		if decoded.Error() != orig.Error() {
+			t.Fatalf("decoded %q, expected %q", decoded, orig)
+		}
+		for idx, err := range decoded.Errors() {
+			ts, ok := ErrorTimeFinder(err)
+			origTs, _ := ErrorTimeFinder(orig.(*AggregateError).errs[idx])
+			if !ok || !ts.Equal(origTs) {
+				t.Fatalf("timestamp not preserved for %v", err)
+			}
+		}
+
+		again, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again) != string(data) {
+			t.Fatalf("round trip changed json: %s != %s", again, data)
+		}
+	})
+	t.Run("Annotations", func(t *testing.T) {
+		for name, opt := range map[string]CatcherOption{
+			"Stack":  WithStackCapture(),
+			"Caller": WithCallerAnnotation(),
+			"Fields": WithFields("shard", 3),
+		} {
+			for _, factory := range []func(...CatcherOption) Catcher{NewBasicCatcher, NewTimestampCatcher} {
+				catcher := factory(opt)
+				catcher.Add(&fs.PathError{Op: "open", Path: "/tmp/foo", Err: fs.ErrNotExist})
+
+				data, err := json.Marshal(catcher.Resolve())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var out []map[string]interface{}
+				if err := json.Unmarshal(data, &out); err != nil {
+					t.Fatal(err)
+				}
+				if out[0]["type"] != "*fs.PathError" {
+					t.Errorf("%s: json should report the collected error: %s", name, data)
+				}
+				if causes, ok := out[0]["causes"].([]interface{}); !ok || len(causes) != 1 {
+					t.Errorf("%s: causes should not include annotations: %s", name, data)
+				}
+			}
+		}
+	})
+	t.Run("InvalidJSON", func(t *testing.T) {
+		if err := json.Unmarshal([]byte(`{"message": 1}`), &AggregateError{}); err == nil {
+			t.Fatal("should not decode invalid input")
+		}
+	})
+}
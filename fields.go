@@ -0,0 +1,152 @@
+package emt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// badFieldKey is the key of a trailing value without a key, as in
+// log/slog.
+const badFieldKey = "!BADKEY"
+
+// fieldError annotates an error with structured key/value context,
+// such as a request ID or an attempt number.
+type fieldError struct {
+	err    error
+	fields map[string]interface{}
+}
+
+// makeFields converts alternating keys and values to a map. Keys that
+// are not strings are formatted with fmt.Sprint, and a final value
+// without a key has the key "!BADKEY".
+func makeFields(kv []interface{}) map[string]interface{} {
+	if len(kv) == 0 {
+		return nil
+	}
+
+	out := make(map[string]interface{}, (len(kv)+1)/2)
+	for idx := 0; idx < len(kv); idx += 2 {
+		if idx+1 == len(kv) {
+			out[badFieldKey] = kv[idx]
+			break
+		}
+
+		key, ok := kv[idx].(string)
+		if !ok {
+			key = fmt.Sprint(kv[idx])
+		}
+		out[key] = kv[idx+1]
+	}
+
+	return out
+}
+
+// WrapErrorFields annotates an error with fields, given as
+// alternating keys and values, as with log/slog: for example,
+// WrapErrorFields(err, "request_id", id, "attempt", 2). The message of
+// the error does not change, but the extended (%+v) and JSON
+// renderings include the fields. Use ErrorFieldsFinder to retrieve
+// them.
+func WrapErrorFields(err error, kv ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	fields := makeFields(kv)
+	if len(fields) == 0 {
+		return err
+	}
+
+	return &fieldError{err: err, fields: fields}
+}
+
+// ErrorFieldsFinder collects the fields of all of the annotations of
+// the error, from WrapErrorFields and the WithFields catcher option,
+// unwrapping the error as needed. When annotations have the same key,
+// the outermost annotation wins. If the error has no fields,
+// ErrorFieldsFinder returns nil and false.
+func ErrorFieldsFinder(err error) (map[string]interface{}, bool) {
+	var out map[string]interface{}
+	for err != nil {
+		switch e := err.(type) {
+		case *fieldError:
+			if e == nil {
+				return out, out != nil
+			}
+			if out == nil {
+				out = make(map[string]interface{}, len(e.fields))
+			}
+			for key, value := range e.fields {
+				if _, ok := out[key]; !ok {
+					out[key] = value
+				}
+			}
+			err = e.err
+		case interface{ Cause() error }:
+			err = e.Cause()
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return out, out != nil
+		}
+	}
+
+	return out, out != nil
+}
+
+func (e *fieldError) Cause() error  { return e.err }
+func (e *fieldError) Unwrap() error { return e.err }
+func (e *fieldError) Error() string { return e.err.Error() }
+
+func (e *fieldError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "%+v [%s]", e.err, renderFields(e.fields))
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = fmt.Fprintf(s, "%s", e.err)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.err.Error())
+	}
+}
+
+// renderFields renders the fields as "key=value" pairs, ordered by
+// key.
+func renderFields(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for idx, key := range keys {
+		pairs[idx] = fmt.Sprintf("%s=%v", key, fields[key])
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// withFields annotates the error with the fields that the error does
+// not have already, so that the fields of the error take precedence
+// over the fields of the catcher.
+func withFields(err error, fields map[string]interface{}) error {
+	existing, _ := ErrorFieldsFinder(err)
+
+	missing := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if _, ok := existing[key]; !ok {
+			missing[key] = value
+		}
+	}
+
+	if len(missing) == 0 {
+		return err
+	}
+
+	return annotateInner(err, func(err error) error { return &fieldError{err: err, fields: missing} })
+}
@@ -0,0 +1,218 @@
+package emt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFields(t *testing.T) {
+	t.Run("Wrap", func(t *testing.T) {
+		if WrapErrorFields(nil, "key", "value") != nil {
+			t.Fatal("wrapping nil should be nil")
+		}
+
+		base := errors.New("hello")
+		if WrapErrorFields(base) != base {
+			t.Fatal("wrapping without fields should return the error")
+		}
+
+		err := WrapErrorFields(base, "request_id", "abc", "attempt", 2)
+		if err.Error() != "hello" {
+			t.Fatalf("fields should not change the message: %q", err)
+		}
+		if !errors.Is(err, base) {
+			t.Fatal("fields should wrap the error")
+		}
+
+		fields, ok := ErrorFieldsFinder(err)
+		if !ok {
+			t.Fatal("should find fields")
+		}
+		if !reflect.DeepEqual(fields, map[string]interface{}{"request_id": "abc", "attempt": 2}) {
+			t.Fatalf("unexpected fields %v", fields)
+		}
+	})
+	t.Run("Keys", func(t *testing.T) {
+		fields, _ := ErrorFieldsFinder(WrapErrorFields(errors.New("hello"), 1, "one", "extra"))
+		if !reflect.DeepEqual(fields, map[string]interface{}{"1": "one", "!BADKEY": "extra"}) {
+			t.Fatalf("unexpected fields %v", fields)
+		}
+	})
+	t.Run("Finder", func(t *testing.T) {
+		if _, ok := ErrorFieldsFinder(nil); ok {
+			t.Fatal("nil errors have no fields")
+		}
+		if _, ok := ErrorFieldsFinder(errors.New("hello")); ok {
+			t.Fatal("plain errors have no fields")
+		}
+
+		err := WrapErrorFields(errors.New("hello"), "shard", 1, "attempt", 1)
+		err = fmt.Errorf("retrying: %w", err)
+		err = WrapErrorTime(err)
+		err = WrapErrorFields(err, "attempt", 2, "request_id", "abc")
+
+		fields, ok := ErrorFieldsFinder(err)
+		if !ok {
+			t.Fatal("should find fields")
+		}
+		expected := map[string]interface{}{"shard": 1, "attempt": 2, "request_id": "abc"}
+		if !reflect.DeepEqual(fields, expected) {
+			t.Fatalf("fields %v should be %v", fields, expected)
+		}
+
+		fields["shard"] = 2
+		if again, _ := ErrorFieldsFinder(err); again["shard"] != 1 {
+			t.Fatal("modifying the result should not modify the error")
+		}
+	})
+	t.Run("Format", func(t *testing.T) {
+		err := WrapErrorFields(errors.New("hello"), "shard", 1, "attempt", 2)
+		for verb, expected := range map[string]string{
+			"%s":  "hello",
+			"%v":  "hello",
+			"%q":  `"hello"`,
+			"%+v": "hello [attempt=2 shard=1]",
+		} {
+			if out := fmt.Sprintf(verb, err); out != expected {
+				t.Errorf("%s rendered %q, expected %q", verb, out, expected)
+			}
+		}
+	})
+}
+
+func TestCatcherFields(t *testing.T) {
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("Disabled", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("hello")
+				if _, ok := ErrorFieldsFinder(catcher.Errors()[0]); ok {
+					t.Fatal("catchers should not add fields by default")
+				}
+			})
+			t.Run("Stamped", func(t *testing.T) {
+				catcher := fix.Factory(WithFields("shard", 3), WithFields("worker", "a"))
+				catcher.New("one")
+				catcher.Errorf("two %d", 2)
+				catcher.Extend([]error{errors.New("three")})
+
+				if catcher.Len() != 3 {
+					t.Fatalf("catcher has %d errors", catcher.Len())
+				}
+				for _, err := range catcher.Errors() {
+					fields, ok := ErrorFieldsFinder(err)
+					if !ok || fields["shard"] != 3 || fields["worker"] != "a" {
+						t.Fatalf("unexpected fields %v for %v", fields, err)
+					}
+					if !strings.Contains(err.Error(), "one") && !strings.Contains(err.Error(), "two") && !strings.Contains(err.Error(), "three") {
+						t.Fatalf("fields should not change the message: %q", err)
+					}
+				}
+			})
+			t.Run("ErrorFieldsWin", func(t *testing.T) {
+				catcher := fix.Factory(WithFields("shard", 3, "worker", "a"))
+				catcher.Add(WrapErrorFields(errors.New("hello"), "shard", 4))
+
+				fields, _ := ErrorFieldsFinder(catcher.Errors()[0])
+				if fields["shard"] != 4 || fields["worker"] != "a" {
+					t.Fatalf("unexpected fields %v", fields)
+				}
+			})
+			t.Run("KeepsTimestamp", func(t *testing.T) {
+				if fix.Name == "Dedup" {
+					t.Skip("deduplicating catchers group errors rather than keeping timestamps")
+				}
+
+				orig := WrapErrorTime(errors.New("hello"))
+				ts, _ := ErrorTimeFinder(orig)
+
+				catcher := fix.Factory(WithFields("shard", 3))
+				catcher.Add(orig)
+
+				err := catcher.Errors()[0]
+				if _, ok := err.(*timestampError); !ok {
+					t.Fatalf("timestamp should be the outermost annotation, not %T", err)
+				}
+				if found, ok := ErrorTimeFinder(err); !ok || !found.Equal(ts) {
+					t.Fatal("fields should retain the timestamp")
+				}
+				if _, ok := ErrorFieldsFinder(err); !ok {
+					t.Fatal("should find fields inside timestamp")
+				}
+			})
+		})
+	}
+	t.Run("Extended", func(t *testing.T) {
+		catcher := NewExtendedCatcher(WithFields("shard", 3))
+		catcher.New("hello")
+		if catcher.String() != "hello [shard=3]" {
+			t.Fatalf("extended catcher should render the fields: %q", catcher)
+		}
+		if !strings.Contains(fmt.Sprintf("%+v", catcher.Resolve()), "shard=3") {
+			t.Fatal("the extended format should render the fields")
+		}
+
+		basic := NewBasicCatcher(WithFields("shard", 3))
+		basic.New("hello")
+		if basic.String() != "hello" {
+			t.Fatalf("basic catcher should not render the fields: %q", basic)
+		}
+	})
+	t.Run("WithCaller", func(t *testing.T) {
+		catcher := NewExtendedCatcher(WithFields("shard", 3), WithCallerAnnotation())
+		catcher.New("hello")
+		if !strings.HasPrefix(catcher.String(), "hello [shard=3]\n\tadded by ") {
+			t.Fatalf("fields should render before the caller: %q", catcher)
+		}
+	})
+	t.Run("JSON", func(t *testing.T) {
+		ts := time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC)
+		catcher := NewTimestampCatcher(WithClock(NewFakeClock(ts)), WithFields("shard", 3))
+		catcher.Add(WrapErrorFields(fmt.Errorf("outer: %w", errors.New("inner")), "request_id", "abc"))
+
+		orig := catcher.Resolve()
+		data, err := json.Marshal(orig)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out []map[string]interface{}
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if len(out) != 1 {
+			t.Fatalf("unexpected output %s", data)
+		}
+		if !reflect.DeepEqual(out[0]["fields"], map[string]interface{}{"shard": 3.0, "request_id": "abc"}) {
+			t.Fatalf("unexpected fields in %s", data)
+		}
+		if out[0]["message"] != "outer: inner" || out[0]["type"] != "*fmt.wrapError" {
+			t.Fatalf("json should report the annotated error: %s", data)
+		}
+		if causes := out[0]["causes"].([]interface{}); len(causes) != 1 {
+			t.Fatalf("causes should not include the fields: %s", data)
+		}
+
+		decoded := &AggregateError{}
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatal(err)
+		}
+		fields, ok := ErrorFieldsFinder(decoded.Errors()[0])
+		if !ok || fields["request_id"] != "abc" {
+			t.Fatalf("decoding should retain the fields: %v", fields)
+		}
+
+		again, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again) != string(data) {
+			t.Fatalf("round trip changed json: %s != %s", again, data)
+		}
+	})
+}
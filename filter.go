@@ -0,0 +1,111 @@
+package emt
+
+import "errors"
+
+// unannotate returns the error that a catcher collected, without the
+// annotations that catchers add, such as timestamps, groups of
+// duplicates, stacks, callers and fields.
+func unannotate(err error) error {
+	for {
+		switch e := err.(type) {
+		case *duplicateError:
+			err = e.err
+		case *timestampError:
+			if e == nil || e.err == nil {
+				return err
+			}
+			err = e.err
+		case *stackError:
+			err = e.err
+		case *callerError:
+			err = e.err
+		case *fieldError:
+			err = e.err
+		default:
+			return err
+		}
+	}
+}
+
+// Filter returns the errors in the catcher, as reported by Errors,
+// for which the predicate returns true. The predicate receives each
+// error as it was added to the catcher, without the annotations that
+// catchers add, such as timestamps, stacks and callers, while the
+// result retains them.
+func Filter(c Catcher, pred func(error) bool) []error {
+	var out []error
+	for _, err := range c.Errors() {
+		if pred(unannotate(err)) {
+			out = append(out, err)
+		}
+	}
+
+	return out
+}
+
+// Count returns the number of errors in the catcher, as reported by
+// Errors, for which the predicate returns true. The predicate
+// receives the errors as Filter does.
+func Count(c Catcher, pred func(error) bool) int {
+	var count int
+	for _, err := range c.Errors() {
+		if pred(unannotate(err)) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Any returns true if errors.Is reports that any error in the
+// catcher matches the target.
+func Any(c Catcher, target error) bool {
+	for _, err := range c.Errors() {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AsAny finds the first error in the catcher that errors.As can
+// assign to the target, sets the target to that error and returns
+// true. Otherwise it returns false. As with errors.As, the target
+// must be a non-nil pointer to a type that implements error, or to
+// any interface type.
+func AsAny(c Catcher, target interface{}) bool {
+	for _, err := range c.Errors() {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Partition splits the errors in the catcher into two new catchers:
+// the first holds the errors for which the predicate returns true,
+// and the second holds the rest. The predicate receives the errors as
+// Filter does. The new catchers are of the same kind as the catcher
+// passed, with the same size and options, and the catcher passed is
+// not modified. Partition returns basic catchers for Catcher
+// implementations outside of this package.
+func Partition(c Catcher, pred func(error) bool) (Catcher, Catcher) {
+	var match, rest Catcher
+	if ec, ok := c.(interface{ emptyCopy() Catcher }); ok {
+		match, rest = ec.emptyCopy(), ec.emptyCopy()
+	} else {
+		match, rest = NewBasicCatcher(), NewBasicCatcher()
+	}
+
+	for _, err := range c.Errors() {
+		if pred(unannotate(err)) {
+			match.Add(err)
+		} else {
+			rest.Add(err)
+		}
+	}
+
+	return match, rest
+}
@@ -0,0 +1,156 @@
+package emt
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	isPathError := func(err error) bool { _, ok := err.(*fs.PathError); return ok }
+	populate := func(c Catcher) {
+		c.New("one")
+		c.Add(&fs.PathError{Op: "open", Path: "/tmp/foo", Err: fs.ErrNotExist})
+		c.Add(context.Canceled)
+		c.Add(&fs.PathError{Op: "stat", Path: "/tmp/bar", Err: fs.ErrPermission})
+	}
+
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("Filter", func(t *testing.T) {
+				catcher := fix.Factory()
+				populate(catcher)
+
+				errs := Filter(catcher, isPathError)
+				if len(errs) != 2 {
+					t.Fatalf("filter found %d errors", len(errs))
+				}
+				if !strings.Contains(errs[0].Error(), "/tmp/foo") || !strings.Contains(errs[1].Error(), "/tmp/bar") {
+					t.Fatalf("filter should retain order: %v", errs)
+				}
+				if len(Filter(NewBasicCatcher(), isPathError)) != 0 {
+					t.Fatal("filter of an empty catcher should be empty")
+				}
+			})
+			t.Run("Count", func(t *testing.T) {
+				catcher := fix.Factory()
+				populate(catcher)
+
+				if n := Count(catcher, isPathError); n != 2 {
+					t.Fatalf("count found %d errors", n)
+				}
+				if n := Count(catcher, func(err error) bool { return err == context.Canceled }); n != 1 {
+					t.Fatalf("predicate should see the collected error, found %d", n)
+				}
+			})
+			t.Run("Any", func(t *testing.T) {
+				catcher := fix.Factory()
+				populate(catcher)
+
+				if !Any(catcher, context.Canceled) {
+					t.Fatal("should find sentinel")
+				}
+				if !Any(catcher, fs.ErrPermission) {
+					t.Fatal("should find wrapped sentinel")
+				}
+				if Any(catcher, context.DeadlineExceeded) {
+					t.Fatal("should not find missing sentinel")
+				}
+			})
+			t.Run("AsAny", func(t *testing.T) {
+				catcher := fix.Factory()
+				populate(catcher)
+
+				var perr *fs.PathError
+				if !AsAny(catcher, &perr) {
+					t.Fatal("should find path error")
+				}
+				if perr.Path != "/tmp/foo" {
+					t.Fatalf("should find the first match, found %v", perr)
+				}
+
+				var panicErr *PanicError
+				if AsAny(catcher, &panicErr) {
+					t.Fatal("should not find missing type")
+				}
+			})
+			t.Run("Partition", func(t *testing.T) {
+				catcher := fix.Factory()
+				populate(catcher)
+
+				match, rest := Partition(catcher, isPathError)
+				if match.Len() != 2 || rest.Len() != 2 {
+					t.Fatalf("partitions have %d and %d errors", match.Len(), rest.Len())
+				}
+				if catcher.Len() != 4 {
+					t.Fatalf("partition should not modify the catcher, has %d", catcher.Len())
+				}
+				if !Any(rest, context.Canceled) || Any(match, context.Canceled) {
+					t.Fatal("sentinel should be in the rest")
+				}
+				if _, ok := ErrorTimeFinder(catcher.Errors()[0]); ok {
+					ts, _ := ErrorTimeFinder(catcher.Errors()[1])
+					mts, _ := ErrorTimeFinder(match.Errors()[0])
+					if !ts.Equal(mts) {
+						t.Fatal("partition should retain timestamps")
+					}
+				}
+
+				match.New("two")
+				if catcher.Len() != 4 {
+					t.Fatal("partitions should not share storage with the catcher")
+				}
+			})
+		})
+	}
+	t.Run("Annotations", func(t *testing.T) {
+		for name, opt := range map[string]CatcherOption{
+			"Stack":  WithStackCapture(),
+			"Caller": WithCallerAnnotation(),
+			"Fields": WithFields("shard", 3),
+		} {
+			for _, fix := range catcherFixtures() {
+				catcher := fix.Factory(opt)
+				populate(catcher)
+
+				if n := Count(catcher, isPathError); n != 2 {
+					t.Errorf("%s/%s: count found %d errors", fix.Name, name, n)
+				}
+				if n := Count(catcher, func(err error) bool { return err == context.Canceled }); n != 1 {
+					t.Errorf("%s/%s: predicate should see the collected error, found %d", fix.Name, name, n)
+				}
+				if match, _ := Partition(catcher, isPathError); match.Len() != 2 {
+					t.Errorf("%s/%s: partition has %d errors", fix.Name, name, match.Len())
+				}
+			}
+		}
+	})
+	t.Run("PartitionDedupKeepsCounts", func(t *testing.T) {
+		catcher := NewDedupCatcher()
+		for i := 0; i < 3; i++ {
+			catcher.New("one")
+			catcher.Add(context.Canceled)
+		}
+
+		match, rest := Partition(catcher, func(err error) bool { return errors.Is(err, context.Canceled) })
+		if match.Len() != 1 || match.Total() != 3 {
+			t.Fatalf("partition should keep the group count: %d groups, %d errors", match.Len(), match.Total())
+		}
+		if rest.Len() != 1 || rest.Total() != 3 {
+			t.Fatalf("partition should keep the group count: %d groups, %d errors", rest.Len(), rest.Total())
+		}
+	})
+	t.Run("PartitionKeepsSize", func(t *testing.T) {
+		catcher := MakeBasicCatcher(2)
+		catcher.New("one")
+
+		match, _ := Partition(catcher, func(error) bool { return true })
+		match.New("two")
+		match.New("three")
+		if match.Len() != 2 || match.Dropped() != 1 {
+			t.Fatalf("partition should have the same size: %d errors, %d dropped", match.Len(), match.Dropped())
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package emt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Formatter renders the errors of a catcher, and of the errors that
+// the catcher resolves, as a single string. The catcher renders each
+// error (e.g. with Error(), %s or %+v, depending on the catcher) and
+// passes the messages, oldest first, along with the number of
+// earlier errors that the catcher discarded. Formatters should
+// return an empty string when there are no messages and no discarded
+// errors.
+//
+// Use the WithFormatter option to set the formatter of a catcher. By
+// default, catchers use NewlineFormatter.
+type Formatter interface {
+	FormatErrors(msgs []string, dropped int) string
+}
+
+// FormatterFunc adapts a function to the Formatter interface.
+type FormatterFunc func(msgs []string, dropped int) string
+
+// FormatErrors calls the function.
+func (fn FormatterFunc) FormatErrors(msgs []string, dropped int) string { return fn(msgs, dropped) }
+
+// NewlineFormatter renders one error per line, followed by a line
+// that reports the number of discarded errors, if any. This is the
+// default format of all catchers.
+func NewlineFormatter() Formatter { return FormatterFunc(formatNewline) }
+
+// NumberedFormatter renders a header with the number of errors,
+// followed by a numbered, indented line for each error, as in:
+//
+//	3 errors occurred:
+//	  1. first
+//	  2. second
+//	  3. third
+//
+// Continuation lines of multi-line errors are indented under the
+// first line of the error.
+func NumberedFormatter() Formatter { return FormatterFunc(formatNumbered) }
+
+// SemicolonFormatter renders all errors on one line, separated by
+// semicolons, which is useful for log messages.
+func SemicolonFormatter() Formatter { return FormatterFunc(formatSemicolon) }
+
+// IndentedFormatter renders each error as a bulleted item, and
+// indents the continuation lines of multi-line errors, such as those
+// with stack traces, under their bullet.
+func IndentedFormatter() Formatter { return FormatterFunc(formatIndented) }
+
+func droppedMessage(dropped int) string {
+	return fmt.Sprintf("... and %d earlier errors omitted", dropped)
+}
+
+// indentLines prefixes all lines of the message after the first with
+// the padding.
+func indentLines(msg, padding string) string {
+	return strings.ReplaceAll(msg, "\n", "\n"+padding)
+}
+
+func formatNewline(msgs []string, dropped int) string {
+	if dropped > 0 {
+		msgs = append(msgs[:len(msgs):len(msgs)], droppedMessage(dropped))
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+func formatNumbered(msgs []string, dropped int) string {
+	if len(msgs) == 0 && dropped == 0 {
+		return ""
+	}
+
+	buf := &strings.Builder{}
+	if len(msgs) == 1 {
+		buf.WriteString("1 error occurred:")
+	} else {
+		fmt.Fprintf(buf, "%d errors occurred:", len(msgs))
+	}
+
+	for idx, msg := range msgs {
+		prefix := "  " + strconv.Itoa(idx+1) + ". "
+		buf.WriteString("\n")
+		buf.WriteString(prefix)
+		buf.WriteString(indentLines(msg, strings.Repeat(" ", len(prefix))))
+	}
+
+	if dropped > 0 {
+		buf.WriteString("\n  ")
+		buf.WriteString(droppedMessage(dropped))
+	}
+
+	return buf.String()
+}
+
+func formatSemicolon(msgs []string, dropped int) string {
+	if dropped > 0 {
+		msgs = append(msgs[:len(msgs):len(msgs)], droppedMessage(dropped))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func formatIndented(msgs []string, dropped int) string {
+	out := make([]string, len(msgs), len(msgs)+1)
+	for idx, msg := range msgs {
+		out[idx] = "- " + indentLines(msg, "  ")
+	}
+
+	if dropped > 0 {
+		out = append(out, "- "+droppedMessage(dropped))
+	}
+
+	return strings.Join(out, "\n")
+}
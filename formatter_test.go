@@ -0,0 +1,111 @@
+package emt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatters(t *testing.T) {
+	msgs := []string{"one", "two\n\tat main.go:12", "three"}
+
+	for _, tc := range []struct {
+		Name     string
+		Format   Formatter
+		Expected string
+		Dropped  string
+		Single   string
+	}{
+		{
+			Name:     "Newline",
+			Format:   NewlineFormatter(),
+			Expected: "one\ntwo\n\tat main.go:12\nthree",
+			Dropped:  "one\ntwo\n\tat main.go:12\nthree\n... and 2 earlier errors omitted",
+			Single:   "one",
+		},
+		{
+			Name:     "Numbered",
+			Format:   NumberedFormatter(),
+			Expected: "3 errors occurred:\n  1. one\n  2. two\n     \tat main.go:12\n  3. three",
+			Dropped:  "3 errors occurred:\n  1. one\n  2. two\n     \tat main.go:12\n  3. three\n  ... and 2 earlier errors omitted",
+			Single:   "1 error occurred:\n  1. one",
+		},
+		{
+			Name:     "Semicolon",
+			Format:   SemicolonFormatter(),
+			Expected: "one; two\n\tat main.go:12; three",
+			Dropped:  "one; two\n\tat main.go:12; three; ... and 2 earlier errors omitted",
+			Single:   "one",
+		},
+		{
+			Name:     "Indented",
+			Format:   IndentedFormatter(),
+			Expected: "- one\n- two\n  \tat main.go:12\n- three",
+			Dropped:  "- one\n- two\n  \tat main.go:12\n- three\n- ... and 2 earlier errors omitted",
+			Single:   "- one",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			if out := tc.Format.FormatErrors(msgs, 0); out != tc.Expected {
+				t.Errorf("rendered %q, expected %q", out, tc.Expected)
+			}
+			if out := tc.Format.FormatErrors(msgs, 2); out != tc.Dropped {
+				t.Errorf("rendered %q, expected %q", out, tc.Dropped)
+			}
+			if out := tc.Format.FormatErrors(msgs[:1], 0); out != tc.Single {
+				t.Errorf("rendered %q, expected %q", out, tc.Single)
+			}
+			if out := tc.Format.FormatErrors(nil, 0); out != "" {
+				t.Errorf("empty input rendered %q", out)
+			}
+
+			input := []string{"one", "two"}
+			tc.Format.FormatErrors(input[:1:2], 1)
+			if input[1] != "two" {
+				t.Error("formatter should not modify its input")
+			}
+		})
+	}
+}
+
+func TestCatcherFormatter(t *testing.T) {
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("Default", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("one")
+				if strings.Contains(catcher.String(), "occurred") {
+					t.Fatalf("catchers should use the newline formatter by default: %s", catcher)
+				}
+			})
+			t.Run("Applied", func(t *testing.T) {
+				catcher := fix.Factory(WithFormatter(NumberedFormatter()))
+				catcher.New("one")
+				catcher.New("two")
+
+				if !strings.HasPrefix(catcher.String(), "1 error occurred:") && !strings.HasPrefix(catcher.String(), "2 errors occurred:") {
+					t.Fatalf("string should use the formatter: %s", catcher)
+				}
+				if catcher.Resolve().Error() != catcher.String() {
+					t.Fatalf("resolved error %q should match %q", catcher.Resolve(), catcher.String())
+				}
+				if catcher.Error() != catcher.String() {
+					t.Fatalf("error %q should match %q", catcher.Error(), catcher.String())
+				}
+				if !strings.Contains(catcher.ResolveAtLeast(LevelError).Error(), "occurred") {
+					t.Fatal("resolving with a threshold should use the formatter")
+				}
+			})
+			t.Run("Func", func(t *testing.T) {
+				catcher := fix.Factory(WithFormatter(FormatterFunc(func(msgs []string, dropped int) string {
+					return strings.ToUpper(strings.Join(msgs, "|"))
+				})))
+				catcher.Add(errors.New("one"))
+
+				if !strings.Contains(catcher.String(), "ONE") {
+					t.Fatalf("string should use the formatter: %s", catcher)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package emt
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs functions in their own goroutines and collects their
+// errors in a Catcher. Group is similar to errgroup.Group, but has
+// continue-on-error semantics: Wait returns the errors from all of
+// the functions, rather than only the first.
+//
+// The zero value is a usable Group, which collects errors in a basic
+// catcher, does not limit the number of active goroutines, and does
+// not cancel its context on error.
+type Group struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	catcher  Catcher
+	sem      chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	failFast bool
+}
+
+// GroupOption configures a Group. Pass options to NewGroup.
+type GroupOption func(*Group)
+
+// WithGroupCatcher sets the catcher that collects the errors of the
+// group. The catcher's options, such as WithPanicRecovery, apply to
+// the functions that the group runs. By default, groups use a basic
+// catcher.
+func WithGroupCatcher(c Catcher) GroupOption {
+	return func(g *Group) {
+		if c != nil {
+			g.catcher = c
+		}
+	}
+}
+
+// WithCancelOnError cancels the group's context when a function
+// returns an error or panics. Functions that are already running
+// continue, and their errors are collected; functions that respect
+// the context, such as those passed to GoCtx, can return early.
+func WithCancelOnError() GroupOption {
+	return func(g *Group) { g.failFast = true }
+}
+
+// NewGroup constructs a Group with a context derived from the
+// context passed. The group cancels its context when Wait returns,
+// or, with the WithCancelOnError option, when a function fails.
+func NewGroup(ctx context.Context, opts ...GroupOption) *Group {
+	g := &Group{}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	g.init()
+
+	return g
+}
+
+func (g *Group) init() {
+	if g.catcher == nil {
+		g.catcher = NewBasicCatcher()
+	}
+
+	if g.ctx == nil {
+		g.ctx, g.cancel = context.WithCancel(context.Background())
+	}
+}
+
+// Context returns the group's context, which GoCtx passes to the
+// functions.
+func (g *Group) Context() context.Context {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.init()
+
+	return g.ctx
+}
+
+// SetLimit limits the number of functions that the group runs at
+// once: when the limit is reached, Go and GoCtx block until a
+// function returns. A limit less than or equal to zero removes the
+// limit. The limit applies to functions started after the call.
+func (g *Group) SetLimit(n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs the function in a new goroutine, and collects its error
+// using the catcher's Check method. Because the function runs in its
+// own goroutine, a panic in the function crashes the program unless
+// the catcher recovers panics.
+func (g *Group) Go(fn CheckFunction) { g.start(fn) }
+
+// GoCtx runs the function, as Go, and passes it the group's context.
+func (g *Group) GoCtx(fn ContextCheckFunction) {
+	ctx := g.Context()
+
+	g.start(func() error { return fn(ctx) })
+}
+
+func (g *Group) start(fn CheckFunction) {
+	g.mu.Lock()
+	g.init()
+	sem, catcher, cancel := g.sem, g.catcher, g.cancel
+	g.mu.Unlock()
+
+	if sem != nil {
+		sem <- struct{}{}
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if sem != nil {
+			defer func() { <-sem }()
+		}
+
+		catcher.Check(func() (err error) {
+			var returned bool
+			defer func() {
+				if g.failFast && (!returned || err != nil) {
+					cancel()
+				}
+			}()
+
+			err = fn()
+			returned = true
+			return err
+		})
+	}()
+}
+
+// Wait blocks until all functions in the group have returned, cancels
+// the group's context, and returns the resolved error of the catcher,
+// which is nil if no function returned an error.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+
+	g.mu.Lock()
+	g.init()
+	catcher, cancel := g.catcher, g.cancel
+	g.mu.Unlock()
+
+	cancel()
+
+	return catcher.Resolve()
+}
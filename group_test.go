@@ -0,0 +1,155 @@
+package emt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("ZeroValue", func(t *testing.T) {
+		g := &Group{}
+		g.Go(func() error { return nil })
+		if err := g.Wait(); err != nil {
+			t.Fatalf("group should not have errors: %v", err)
+		}
+	})
+	t.Run("CollectsAllErrors", func(t *testing.T) {
+		g := NewGroup(ctx)
+		for idx := 0; idx < 40; idx++ {
+			idx := idx
+			g.Go(func() error {
+				if idx%2 == 0 {
+					return fmt.Errorf("check %d", idx)
+				}
+				return nil
+			})
+		}
+
+		var agg *AggregateError
+		if !errors.As(g.Wait(), &agg) {
+			t.Fatal("group should return an aggregate error")
+		}
+		if len(agg.Errors()) != 20 {
+			t.Fatalf("group collected %d errors", len(agg.Errors()))
+		}
+	})
+	t.Run("PluggableCatcher", func(t *testing.T) {
+		catcher := NewDedupCatcher()
+		g := NewGroup(ctx, WithGroupCatcher(catcher))
+		for idx := 0; idx < 10; idx++ {
+			g.Go(func() error { return errors.New("same") })
+		}
+
+		if err := g.Wait(); err == nil {
+			t.Fatal("group should have errors")
+		}
+		if catcher.Len() != 1 || catcher.Total() != 10 {
+			t.Fatalf("group should use the catcher: %d groups, %d errors", catcher.Len(), catcher.Total())
+		}
+	})
+	t.Run("RespectsLimit", func(t *testing.T) {
+		var running, peak int64
+		g := NewGroup(ctx)
+		g.SetLimit(4)
+		for idx := 0; idx < 32; idx++ {
+			g.Go(func() error {
+				cur := atomic.AddInt64(&running, 1)
+				for {
+					prev := atomic.LoadInt64(&peak)
+					if cur <= prev || atomic.CompareAndSwapInt64(&peak, prev, cur) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt64(&running, -1)
+				return nil
+			})
+		}
+
+		if err := g.Wait(); err != nil {
+			t.Fatal(err)
+		}
+		if peak > 4 {
+			t.Fatalf("group ran %d functions at once", peak)
+		}
+	})
+	t.Run("GoCtxReceivesGroupContext", func(t *testing.T) {
+		g := NewGroup(ctx)
+		g.GoCtx(func(gctx context.Context) error {
+			if gctx != g.Context() {
+				return errors.New("function should receive the group context")
+			}
+			return nil
+		})
+
+		if err := g.Wait(); err != nil {
+			t.Fatal(err)
+		}
+		if g.Context().Err() == nil {
+			t.Fatal("group context should be canceled after wait")
+		}
+	})
+	t.Run("ContinuesOnError", func(t *testing.T) {
+		g := NewGroup(ctx)
+		g.Go(func() error { return errors.New("first") })
+		g.GoCtx(func(gctx context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			return gctx.Err()
+		})
+
+		var agg *AggregateError
+		if !errors.As(g.Wait(), &agg) || len(agg.Errors()) != 1 {
+			t.Fatalf("the context should not be canceled on error: %v", agg)
+		}
+	})
+	t.Run("CancelOnError", func(t *testing.T) {
+		g := NewGroup(ctx, WithCancelOnError())
+		g.Go(func() error { return errors.New("first") })
+		g.GoCtx(func(gctx context.Context) error {
+			select {
+			case <-gctx.Done():
+				return gctx.Err()
+			case <-time.After(time.Minute):
+				return errors.New("context should be canceled")
+			}
+		})
+
+		err := g.Wait()
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("the context should be canceled on error: %v", err)
+		}
+		if agg := err.(*AggregateError); len(agg.Errors()) != 2 {
+			t.Fatalf("group should collect all errors, has %d", len(agg.Errors()))
+		}
+	})
+	t.Run("CancelOnPanic", func(t *testing.T) {
+		g := NewGroup(ctx, WithCancelOnError(), WithGroupCatcher(NewBasicCatcher(WithPanicRecovery())))
+		g.Go(func() error { panic("boom") })
+		g.GoCtx(func(gctx context.Context) error {
+			<-gctx.Done()
+			return nil
+		})
+
+		var perr *PanicError
+		if !errors.As(g.Wait(), &perr) {
+			t.Fatal("group should collect the panic")
+		}
+	})
+	t.Run("ParentCancellation", func(t *testing.T) {
+		pctx, pcancel := context.WithCancel(ctx)
+		g := NewGroup(pctx)
+		pcancel()
+
+		if g.Context().Err() == nil {
+			t.Fatal("group context should derive from the parent")
+		}
+		_ = g.Wait()
+	})
+}
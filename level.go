@@ -0,0 +1,114 @@
+package emt
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Level is the severity of a collected error. Errors without a level
+// have LevelError.
+type Level int
+
+const (
+	LevelInfo Level = iota + 1
+	LevelWarning
+	LevelError
+	LevelFatal
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelInfo:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	case LevelFatal:
+		return "fatal"
+	default:
+		return "level(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+type levelError struct {
+	err   error
+	level Level
+}
+
+// WrapErrorLevel annotates an error with a severity level. The
+// message of the annotated error starts with the level tag, e.g.
+// "[warning] ...". Use ErrorLevelFinder to retrieve the level.
+func WrapErrorLevel(level Level, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &levelError{err: err, level: level}
+}
+
+// ErrorLevelFinder finds the level of an error annotated with
+// WrapErrorLevel, or added to a catcher with AddLevel, unwrapping the
+// error as needed. If the error has no level, ErrorLevelFinder returns
+// LevelError and false.
+func ErrorLevelFinder(err error) (Level, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *levelError:
+			if e == nil {
+				return LevelError, false
+			}
+			return e.level, true
+		case interface{ Cause() error }:
+			err = e.Cause()
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return LevelError, false
+		}
+	}
+
+	return LevelError, false
+}
+
+func (e *levelError) Cause() error  { return e.err }
+func (e *levelError) Unwrap() error { return e.err }
+func (e *levelError) Error() string { return fmt.Sprintf("[%s] %s", e.level, e.err.Error()) }
+
+func (e *levelError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "[%s] %+v", e.level, e.err)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = fmt.Fprintf(s, "[%s] %s", e.level, e.err)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "[%s] %q", e.level, e.err)
+	}
+}
+
+// filterLevel returns the errors with a level at or above the
+// threshold.
+func filterLevel(errs []error, threshold Level) []error {
+	var out []error
+	for _, err := range errs {
+		if level, _ := ErrorLevelFinder(err); level >= threshold {
+			out = append(out, err)
+		}
+	}
+
+	return out
+}
+
+func hasLevel(errs []error, threshold Level) bool {
+	for _, err := range errs {
+		if level, _ := ErrorLevelFinder(err); level >= threshold {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,137 @@
+package emt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLevel(t *testing.T) {
+	t.Run("String", func(t *testing.T) {
+		for level, expected := range map[Level]string{
+			LevelInfo:    "info",
+			LevelWarning: "warning",
+			LevelError:   "error",
+			LevelFatal:   "fatal",
+			Level(42):    "level(42)",
+		} {
+			if level.String() != expected {
+				t.Errorf("level %d is %q, expected %q", level, level, expected)
+			}
+		}
+	})
+	t.Run("Ordering", func(t *testing.T) {
+		if !(LevelInfo < LevelWarning && LevelWarning < LevelError && LevelError < LevelFatal) {
+			t.Fatal("levels should increase in severity")
+		}
+	})
+	t.Run("WrapNil", func(t *testing.T) {
+		if WrapErrorLevel(LevelWarning, nil) != nil {
+			t.Fatal("wrapping nil should produce nil")
+		}
+	})
+	t.Run("Finder", func(t *testing.T) {
+		base := errors.New("base")
+		err := WrapErrorLevel(LevelWarning, base)
+
+		if level, ok := ErrorLevelFinder(err); !ok || level != LevelWarning {
+			t.Fatalf("found level %s", level)
+		}
+		if level, ok := ErrorLevelFinder(fmt.Errorf("outer: %w", WrapErrorTime(err))); !ok || level != LevelWarning {
+			t.Fatalf("should find wrapped level, found %s", level)
+		}
+		if level, ok := ErrorLevelFinder(base); ok || level != LevelError {
+			t.Fatalf("errors without a level should be errors, found %s", level)
+		}
+		if level, ok := ErrorLevelFinder(nil); ok || level != LevelError {
+			t.Fatalf("nil errors should not have a level, found %s", level)
+		}
+		if !errors.Is(err, base) {
+			t.Fatal("level annotation should wrap the error")
+		}
+	})
+	t.Run("Format", func(t *testing.T) {
+		err := WrapErrorLevel(LevelFatal, errors.New("base"))
+		for verb, expected := range map[string]string{
+			"%s":  "[fatal] base",
+			"%v":  "[fatal] base",
+			"%+v": "[fatal] base",
+			"%q":  `[fatal] "base"`,
+		} {
+			if out := fmt.Sprintf(verb, err); out != expected {
+				t.Errorf("%s rendered %q, expected %q", verb, out, expected)
+			}
+		}
+		if err.Error() != "[fatal] base" {
+			t.Fatalf("unexpected message %q", err.Error())
+		}
+	})
+}
+
+func TestCatcherLevels(t *testing.T) {
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("HasErrorsAtLeast", func(t *testing.T) {
+				catcher := fix.Factory()
+				if catcher.HasErrorsAtLeast(LevelInfo) {
+					t.Fatal("empty catcher should not have errors")
+				}
+
+				catcher.AddLevel(LevelWarning, errors.New("careful"))
+				if !catcher.HasErrorsAtLeast(LevelWarning) {
+					t.Fatal("catcher should have warnings")
+				}
+				if catcher.HasErrorsAtLeast(LevelError) {
+					t.Fatal("catcher should not have errors")
+				}
+
+				catcher.New("unleveled")
+				if !catcher.HasErrorsAtLeast(LevelError) || catcher.HasErrorsAtLeast(LevelFatal) {
+					t.Fatal("unleveled errors should be errors")
+				}
+			})
+			t.Run("AddLevelNil", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.AddLevel(LevelFatal, nil)
+				assertCatcherEmpty(t, catcher)
+			})
+			t.Run("ResolveAtLeast", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.AddLevel(LevelInfo, errors.New("one"))
+				catcher.AddLevel(LevelWarning, errors.New("two"))
+				catcher.New("three")
+				catcher.AddLevel(LevelFatal, errors.New("four"))
+
+				if err := catcher.ResolveAtLeast(LevelError); err == nil {
+					t.Fatal("should resolve errors")
+				} else if n := len(err.(*AggregateError).Errors()); n != 2 {
+					t.Fatalf("resolved %d errors", n)
+				} else if strings.Contains(err.Error(), "two") || !strings.Contains(err.Error(), "four") {
+					t.Fatalf("resolved the wrong errors: %s", err)
+				}
+
+				if n := len(catcher.ResolveAtLeast(LevelInfo).(*AggregateError).Errors()); n != 4 {
+					t.Fatalf("resolved %d errors", n)
+				}
+				if catcher.ResolveAtLeast(Level(100)) != nil {
+					t.Fatal("should not resolve errors above every level")
+				}
+				if catcher.Len() != 4 {
+					t.Fatal("resolving should not modify the catcher")
+				}
+			})
+			t.Run("StringHasTag", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.AddLevel(LevelWarning, errors.New("careful"))
+
+				if !strings.Contains(catcher.String(), "[warning] careful") {
+					t.Fatalf("string should have the level tag: %s", catcher.String())
+				}
+				if !strings.Contains(catcher.Resolve().Error(), "[warning] careful") {
+					t.Fatalf("resolved error should have the level tag: %s", catcher.Resolve())
+				}
+			})
+		})
+	}
+}
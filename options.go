@@ -0,0 +1,241 @@
+package emt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CatcherOption configures a Catcher at construction time. All of
+// the Catcher constructors in this package accept options.
+type CatcherOption func(*catcherOptions)
+
+type catcherOptions struct {
+	recoverPanics bool
+	checkTimeout  time.Duration
+	clock         Clock
+	utc           bool
+	layout        string
+	loc           *time.Location
+	formatter     Formatter
+	captureStacks bool
+	recordCaller  bool
+	fields        map[string]interface{}
+}
+
+func makeCatcherOptions(opts []CatcherOption) catcherOptions {
+	conf := catcherOptions{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	return conf
+}
+
+// WithClock sets the clock that the timestamp, time window and
+// deduplicating catchers, as well as WrapErrorTime and
+// WrapErrorTimeMessage, use to annotate errors, and that time window
+// catchers use to find expired errors. By default, they use the
+// system clock. A nil clock is ignored.
+func WithClock(clock Clock) CatcherOption {
+	return func(conf *catcherOptions) {
+		if clock != nil {
+			conf.clock = clock
+		}
+	}
+}
+
+// WithUTCTimestamps converts the times that annotate errors to UTC,
+// so that timestamps render the same regardless of the local time
+// zone. Converting a time to UTC removes its monotonic clock reading,
+// so the timestamps compare by wall clock time.
+func WithUTCTimestamps() CatcherOption {
+	return func(conf *catcherOptions) { conf.utc = true }
+}
+
+// WithTimestampLayout sets the layout, as with time.Format, that
+// timestamp annotations use to render the time of the error in all
+// formats. The default is time.RFC3339. An empty layout is ignored.
+func WithTimestampLayout(layout string) CatcherOption {
+	return func(conf *catcherOptions) {
+		if layout != "" {
+			conf.layout = layout
+		}
+	}
+}
+
+// WithRFC3339NanoTimestamps renders timestamp annotations with
+// nanosecond precision, so that errors collected in the same second
+// have distinct timestamps. It is the same as
+// WithTimestampLayout(time.RFC3339Nano).
+func WithRFC3339NanoTimestamps() CatcherOption { return WithTimestampLayout(time.RFC3339Nano) }
+
+// WithTimestampLocation renders timestamp annotations in the
+// location, such as time.UTC or time.Local. Unlike
+// WithUTCTimestamps, the location changes only the rendering of the
+// timestamp, not the time that ErrorTimeFinder reports. A nil
+// location is ignored.
+func WithTimestampLocation(loc *time.Location) CatcherOption {
+	return func(conf *catcherOptions) {
+		if loc != nil {
+			conf.loc = loc
+		}
+	}
+}
+
+// WithFormatter sets the formatter that the catcher uses to combine
+// the messages of its errors in String, Error and the errors that it
+// resolves. The catcher still renders each error in its own way
+// (e.g. with Error(), %s or %+v). A nil formatter is ignored.
+func WithFormatter(f Formatter) CatcherOption {
+	return func(conf *catcherOptions) {
+		if f != nil {
+			conf.formatter = f
+		}
+	}
+}
+
+// WithStackCapture makes the catcher capture the stack of the code
+// that adds each error, unless the error has a stack trace already,
+// as errors from github.com/pkg/errors do. The catcher resolves the
+// frames only when they are rendered: the %+v format, and catchers
+// that use it, print the stack after the error, and
+// ErrorStackFinder returns the frames.
+func WithStackCapture() CatcherOption {
+	return func(conf *catcherOptions) { conf.captureStacks = true }
+}
+
+// WithCallerAnnotation makes the catcher record the function, file
+// and line of the code that adds each error, skipping the frames of
+// this package, such as those of AddWhen, Errorf and Check. The %+v
+// format, and the catchers that use it, print the caller after the
+// error, and ErrorCallerFinder returns it.
+func WithCallerAnnotation() CatcherOption {
+	return func(conf *catcherOptions) { conf.recordCaller = true }
+}
+
+// WithFields makes the catcher annotate each error that it collects
+// with fixed fields, given as alternating keys and values as with
+// WrapErrorFields, such as the shard that a worker processes. The
+// fields of the error, if any, take precedence over the fields of
+// the catcher. Repeated options combine their fields.
+func WithFields(kv ...interface{}) CatcherOption {
+	fields := makeFields(kv)
+	return func(conf *catcherOptions) {
+		if len(fields) == 0 {
+			return
+		}
+		if conf.fields == nil {
+			conf.fields = make(map[string]interface{}, len(fields))
+		}
+		for key, value := range fields {
+			conf.fields[key] = value
+		}
+	}
+}
+
+// stack captures the stack of the caller, when the options enable
+// stack capture or caller annotation.
+func (conf *catcherOptions) stack() []uintptr {
+	if !conf.captureStacks && !conf.recordCaller {
+		return nil
+	}
+
+	return captureStack()
+}
+
+// annotate applies the field, caller and stack annotations that the
+// options enable to the error.
+func (conf *catcherOptions) annotate(err error, pcs []uintptr) error {
+	if len(conf.fields) > 0 {
+		err = withFields(err, conf.fields)
+	}
+
+	if conf.recordCaller {
+		err = withCaller(err, pcs)
+	}
+
+	if conf.captureStacks {
+		err = withStack(err, pcs)
+	}
+
+	return err
+}
+
+// now returns the current time from the clock, according to the
+// options.
+func (conf *catcherOptions) now() time.Time {
+	ts := conf.clock.Now()
+	if conf.utc {
+		ts = ts.UTC()
+	}
+
+	return ts
+}
+
+// WithPanicRecovery makes the Check, CheckWhen and CheckExtend
+// methods of the catcher recover panics in the CheckFunction, and
+// collect them as *PanicError values, so that one panicking check
+// does not abort the remaining checks.
+func WithPanicRecovery() CatcherOption {
+	return func(conf *catcherOptions) { conf.recoverPanics = true }
+}
+
+// WithCheckTimeout limits the duration of each check run by the
+// CheckCtx, CheckCtxWhen and CheckCtxExtend methods of the catcher:
+// the check receives a context that expires after the timeout, and
+// when a check runs past the timeout the catcher collects a
+// *CheckTimeoutError. A timeout less than or equal to zero disables
+// the limit.
+func WithCheckTimeout(timeout time.Duration) CatcherOption {
+	return func(conf *catcherOptions) { conf.checkTimeout = timeout }
+}
+
+// CheckTimeoutError is the error collected when a check exceeds the
+// timeout set with WithCheckTimeout. It wraps the error returned by
+// the check, if any.
+type CheckTimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *CheckTimeoutError) Unwrap() error { return e.Err }
+func (e *CheckTimeoutError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("check exceeded timeout of %s", e.Timeout)
+	}
+
+	return fmt.Sprintf("check exceeded timeout of %s: %v", e.Timeout, e.Err)
+}
+
+// checkCtx runs the context check function, according to the
+// options, unless the context is already done.
+func (conf *catcherOptions) checkCtx(ctx context.Context, fn ContextCheckFunction) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if conf.checkTimeout <= 0 {
+		return conf.check(func() error { return fn(ctx) })
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, conf.checkTimeout)
+	defer cancel()
+
+	err := conf.check(func() error { return fn(tctx) })
+	if ctx.Err() == nil && errors.Is(tctx.Err(), context.DeadlineExceeded) {
+		return &CheckTimeoutError{Timeout: conf.checkTimeout, Err: err}
+	}
+
+	return err
+}
+
+// check runs the check function, according to the options.
+func (conf *catcherOptions) check(fn CheckFunction) error {
+	if conf.recoverPanics {
+		return recoverCheck(fn)
+	}
+
+	return fn()
+}
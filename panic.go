@@ -0,0 +1,56 @@
+package emt
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error collected when a CheckFunction panics in a
+// catcher constructed with WithPanicRecovery. Use errors.As to find
+// panics among collected errors.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine at the time of the
+	// panic, as reported by runtime/debug.Stack.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("check function panicked: %v", e.Value) }
+
+// Unwrap returns the panic value when it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// Format renders the error, and with %+v includes the stack trace.
+func (e *PanicError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "%s\n%s", e.Error(), e.Stack)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = fmt.Fprint(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// recoverCheck runs the check function, converting a panic into a
+// *PanicError.
+func recoverCheck(fn CheckFunction) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,90 @@
+package emt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	panicker := func() error { panic("validator exploded") }
+
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("Check", func(t *testing.T) {
+				catcher := fix.Factory(WithPanicRecovery())
+				catcher.Check(panicker)
+
+				var perr *PanicError
+				if !errors.As(catcher.Resolve(), &perr) {
+					t.Fatalf("panic should be collected: %v", catcher.Resolve())
+				}
+				if perr.Value != "validator exploded" {
+					t.Fatalf("unexpected panic value %v", perr.Value)
+				}
+				if !strings.Contains(string(perr.Stack), "panic_test.go") {
+					t.Fatalf("stack should include the panicking function: %s", perr.Stack)
+				}
+			})
+			t.Run("CheckWhen", func(t *testing.T) {
+				catcher := fix.Factory(WithPanicRecovery())
+				catcher.CheckWhen(false, panicker)
+				assertCatcherEmpty(t, catcher)
+
+				catcher.CheckWhen(true, panicker)
+				assertCatcherHasErrors(t, catcher, 1)
+			})
+			t.Run("CheckExtendContinues", func(t *testing.T) {
+				catcher := fix.Factory(WithPanicRecovery())
+				catcher.CheckExtend([]CheckFunction{
+					panicker,
+					func() error { return errors.New("after") },
+				})
+
+				if !strings.Contains(catcher.String(), "after") {
+					t.Fatalf("checks after a panic should run: %s", catcher.String())
+				}
+			})
+			t.Run("PanicWithError", func(t *testing.T) {
+				catcher := fix.Factory(WithPanicRecovery())
+				catcher.Check(func() error { panic(io.ErrUnexpectedEOF) })
+
+				if !errors.Is(catcher.Resolve(), io.ErrUnexpectedEOF) {
+					t.Fatal("panics with error values should unwrap to the value")
+				}
+			})
+			t.Run("DisabledByDefault", func(t *testing.T) {
+				catcher := fix.Factory()
+				defer func() {
+					if recover() == nil {
+						t.Fatal("panic should propagate without the option")
+					}
+				}()
+				catcher.Check(panicker)
+			})
+		})
+	}
+
+	t.Run("Formatting", func(t *testing.T) {
+		err := recoverCheck(panicker)
+		if err.Error() != "check function panicked: validator exploded" {
+			t.Fatalf("unexpected message %q", err.Error())
+		}
+		if out := fmt.Sprintf("%v", err); out != err.Error() {
+			t.Fatalf("unexpected %%v output %q", out)
+		}
+		if out := fmt.Sprintf("%+v", err); !strings.HasPrefix(out, err.Error()) || !strings.Contains(out, "goroutine") {
+			t.Fatalf("unexpected %%+v output %q", out)
+		}
+		if out := fmt.Sprintf("%q", err); out != `"check function panicked: validator exploded"` {
+			t.Fatalf("unexpected %%q output %q", out)
+		}
+	})
+	t.Run("NoPanic", func(t *testing.T) {
+		if err := recoverCheck(func() error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
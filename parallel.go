@@ -0,0 +1,91 @@
+package emt
+
+import (
+	"context"
+	"sync"
+)
+
+// CheckParallel runs the check functions concurrently, with no more
+// than limit functions running at once, and collects their errors in
+// the catcher, using the catcher's Check method, so catcher options
+// such as WithPanicRecovery apply. When the limit is less than or
+// equal to zero, all functions run at once. CheckParallel returns
+// when all functions that it started have returned.
+//
+// If the context is canceled, CheckParallel does not start any more
+// functions and adds the context's error to the catcher. Because the
+// functions run in their own goroutines, a panic in a function
+// crashes the program unless the catcher recovers panics.
+func CheckParallel(ctx context.Context, c Catcher, limit int, fns ...CheckFunction) {
+	checkParallel(ctx, c, limit, false, fns)
+}
+
+// CheckParallelFailFast runs the check functions concurrently, as
+// CheckParallel, but does not start any more functions once a
+// function returns an error or panics. Functions that are already
+// running are not interrupted, and their errors are collected.
+func CheckParallelFailFast(ctx context.Context, c Catcher, limit int, fns ...CheckFunction) {
+	checkParallel(ctx, c, limit, true, fns)
+}
+
+func checkParallel(ctx context.Context, c Catcher, limit int, failFast bool, fns []CheckFunction) {
+	if len(fns) == 0 {
+		return
+	}
+
+	if limit <= 0 || limit > len(fns) {
+		limit = len(fns)
+	}
+
+	stopCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	sem := make(chan struct{}, limit)
+	wg := &sync.WaitGroup{}
+
+	var skipped bool
+LOOP:
+	for _, fn := range fns {
+		if fn == nil {
+			continue
+		}
+
+		select {
+		case <-stopCtx.Done():
+			skipped = true
+			break LOOP
+		case sem <- struct{}{}:
+		}
+
+		// select chooses randomly when both cases are ready.
+		if stopCtx.Err() != nil {
+			skipped = true
+			break LOOP
+		}
+
+		wg.Add(1)
+		go func(fn CheckFunction) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			c.Check(func() (err error) {
+				var returned bool
+				defer func() {
+					if failFast && (!returned || err != nil) {
+						stop()
+					}
+				}()
+
+				err = fn()
+				returned = true
+				return err
+			})
+		}(fn)
+	}
+
+	wg.Wait()
+
+	if skipped {
+		c.Add(ctx.Err())
+	}
+}
@@ -0,0 +1,176 @@
+package emt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckParallel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("CollectsAllErrors", func(t *testing.T) {
+		catcher := NewBasicCatcher()
+		fns := make([]CheckFunction, 40)
+		for idx := range fns {
+			idx := idx
+			fns[idx] = func() error {
+				if idx%2 == 0 {
+					return fmt.Errorf("check %d", idx)
+				}
+				return nil
+			}
+		}
+
+		CheckParallel(ctx, catcher, 8, fns...)
+		if catcher.Len() != 20 {
+			t.Fatalf("catcher has %d errors", catcher.Len())
+		}
+	})
+	t.Run("RespectsLimit", func(t *testing.T) {
+		var running, peak int64
+		fn := func() error {
+			cur := atomic.AddInt64(&running, 1)
+			for {
+				prev := atomic.LoadInt64(&peak)
+				if cur <= prev || atomic.CompareAndSwapInt64(&peak, prev, cur) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&running, -1)
+			return nil
+		}
+
+		fns := make([]CheckFunction, 32)
+		for idx := range fns {
+			fns[idx] = fn
+		}
+
+		catcher := NewBasicCatcher()
+		CheckParallel(ctx, catcher, 4, fns...)
+		if peak > 4 {
+			t.Fatalf("ran %d functions at once", peak)
+		}
+		assertCatcherEmpty(t, catcher)
+	})
+	t.Run("RunsConcurrently", func(t *testing.T) {
+		// every check waits until all of the checks are running,
+		// which only happens when they run at once.
+		const size = 40
+		var arrived int64
+		ready := make(chan struct{})
+		wctx, wcancel := context.WithTimeout(ctx, 10*time.Second)
+		defer wcancel()
+
+		fns := make([]CheckFunction, size)
+		for idx := range fns {
+			fns[idx] = func() error {
+				if atomic.AddInt64(&arrived, 1) == size {
+					close(ready)
+				}
+
+				select {
+				case <-ready:
+					return nil
+				case <-wctx.Done():
+					return errors.New("checks did not run at once")
+				}
+			}
+		}
+
+		catcher := NewBasicCatcher()
+		CheckParallel(ctx, catcher, 0, fns...)
+		if catcher.HasErrors() {
+			t.Fatalf("unlimited checks should run concurrently: %v", catcher.Resolve())
+		}
+	})
+	t.Run("Empty", func(t *testing.T) {
+		catcher := NewBasicCatcher()
+		CheckParallel(ctx, catcher, 4)
+		CheckParallel(ctx, catcher, 4, nil, nil)
+		assertCatcherEmpty(t, catcher)
+	})
+	t.Run("CanceledContext", func(t *testing.T) {
+		cctx, ccancel := context.WithCancel(ctx)
+		ccancel()
+
+		var calls int64
+		catcher := NewBasicCatcher()
+		CheckParallel(cctx, catcher, 1, func() error { atomic.AddInt64(&calls, 1); return nil })
+
+		if calls != 0 {
+			t.Fatal("no checks should run after cancellation")
+		}
+		if !errors.Is(catcher.Resolve(), context.Canceled) {
+			t.Fatalf("cancellation should be collected: %v", catcher.Resolve())
+		}
+	})
+	t.Run("CancelDuringChecks", func(t *testing.T) {
+		cctx, ccancel := context.WithCancel(ctx)
+		defer ccancel()
+
+		var calls int64
+		fns := make([]CheckFunction, 10)
+		for idx := range fns {
+			fns[idx] = func() error {
+				if atomic.AddInt64(&calls, 1) == 2 {
+					ccancel()
+				}
+				return nil
+			}
+		}
+
+		catcher := NewBasicCatcher()
+		CheckParallel(cctx, catcher, 1, fns...)
+		if calls >= 10 {
+			t.Fatal("checks should stop after cancellation")
+		}
+		if !errors.Is(catcher.Resolve(), context.Canceled) {
+			t.Fatalf("cancellation should be collected: %v", catcher.Resolve())
+		}
+	})
+	t.Run("FailFast", func(t *testing.T) {
+		var calls int64
+		fns := make([]CheckFunction, 10)
+		for idx := range fns {
+			fns[idx] = func() error {
+				if atomic.AddInt64(&calls, 1) == 3 {
+					return errors.New("failed")
+				}
+				return nil
+			}
+		}
+
+		catcher := NewBasicCatcher()
+		CheckParallelFailFast(ctx, catcher, 1, fns...)
+		if calls != 3 {
+			t.Fatalf("should stop after the first error, ran %d", calls)
+		}
+		if catcher.Len() != 1 || catcher.String() != "failed" {
+			t.Fatalf("only the check error should be collected: %v", catcher.Resolve())
+		}
+	})
+	t.Run("FailFastOnPanic", func(t *testing.T) {
+		var calls int64
+		fns := []CheckFunction{
+			func() error { atomic.AddInt64(&calls, 1); panic("boom") },
+			func() error { atomic.AddInt64(&calls, 1); return nil },
+		}
+
+		catcher := NewBasicCatcher(WithPanicRecovery())
+		CheckParallelFailFast(ctx, catcher, 1, fns...)
+		if calls != 1 {
+			t.Fatalf("should stop after a panic, ran %d", calls)
+		}
+
+		var perr *PanicError
+		if !errors.As(catcher.Resolve(), &perr) {
+			t.Fatalf("panic should be collected: %v", catcher.Resolve())
+		}
+	})
+}
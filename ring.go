@@ -0,0 +1,100 @@
+package emt
+
+// errorRing is the storage used by the catchers. When the limit is
+// greater than zero, the ring never holds more than limit errors: the
+// backing array is allocated once, at construction, and once full,
+// adding an error overwrites the oldest error in place. When the
+// limit is zero the ring grows without bound. The ring counts the
+// errors it evicts to stay within the limit, and, separately, the
+// errors that expire. The ring is not safe for concurrent use; the
+// catchers guard it with their own locks.
+type errorRing struct {
+	errs    []error
+	head    int
+	limit   int
+	dropped int
+	expired int
+}
+
+func makeErrorRing(limit int) errorRing {
+	if limit < 0 {
+		limit = 0
+	}
+
+	return errorRing{errs: make([]error, 0, limit), limit: limit}
+}
+
+func (r *errorRing) push(err error) {
+	if r.limit <= 0 || len(r.errs) < r.limit {
+		r.errs = append(r.errs, err)
+		return
+	}
+
+	r.errs[r.head] = err
+	r.head = (r.head + 1) % r.limit
+	r.dropped++
+}
+
+func (r *errorRing) len() int   { return len(r.errs) }
+func (r *errorRing) cap() int   { return cap(r.errs) }
+func (r *errorRing) total() int { return len(r.errs) + r.dropped + r.expired }
+
+// reset empties the ring and its counters, but retains the backing
+// array for reuse.
+func (r *errorRing) reset() {
+	for idx := range r.errs {
+		r.errs[idx] = nil
+	}
+
+	r.errs = r.errs[:0]
+	r.head = 0
+	r.dropped = 0
+	r.expired = 0
+}
+
+// at returns the error at the index, counting from the oldest error.
+func (r *errorRing) at(idx int) error { return r.errs[(r.head+idx)%len(r.errs)] }
+
+// expireOldest removes the n oldest errors, and counts them as
+// expired. Bounded rings keep their backing array, while unbounded
+// rings release the slots of the removed errors as they grow.
+func (r *errorRing) expireOldest(n int) {
+	if n <= 0 {
+		return
+	}
+	if n > len(r.errs) {
+		n = len(r.errs)
+	}
+
+	if r.head != 0 {
+		copy(r.errs, r.slice())
+		r.head = 0
+	}
+
+	for idx := 0; idx < n; idx++ {
+		r.errs[idx] = nil
+	}
+
+	if r.limit > 0 {
+		size := copy(r.errs, r.errs[n:])
+		for idx := size; idx < len(r.errs); idx++ {
+			r.errs[idx] = nil
+		}
+		r.errs = r.errs[:size]
+	} else {
+		r.errs = r.errs[n:]
+	}
+
+	r.expired += n
+}
+
+// slice returns a copy of the contents of the ring ordered from
+// oldest to newest.
+func (r *errorRing) slice() []error {
+	out := make([]error, len(r.errs))
+
+	n := copy(out, r.errs[r.head:])
+	copy(out[n:], r.errs[:r.head])
+
+	return out
+}
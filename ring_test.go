@@ -0,0 +1,136 @@
+package emt
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestErrorRing(t *testing.T) {
+	assertContents := func(t *testing.T, r *errorRing, expected ...string) {
+		t.Helper()
+		errs := r.slice()
+		if len(errs) != len(expected) || r.len() != len(expected) {
+			t.Fatalf("ring has %d errors, expected %d", len(errs), len(expected))
+		}
+		for idx := range errs {
+			if errs[idx].Error() != expected[idx] {
+				t.Fatalf("at %d, error %q should be %q", idx, errs[idx], expected[idx])
+			}
+		}
+	}
+
+	t.Run("Unbounded", func(t *testing.T) {
+		r := makeErrorRing(0)
+		for i := 0; i < 5; i++ {
+			r.push(errors.New(strconv.Itoa(i)))
+		}
+		assertContents(t, &r, "0", "1", "2", "3", "4")
+	})
+	t.Run("NegativeLimit", func(t *testing.T) {
+		r := makeErrorRing(-1)
+		if r.cap() != 0 {
+			t.Fatalf("capacity should be zero, not %d", r.cap())
+		}
+		r.push(errors.New("0"))
+		r.push(errors.New("1"))
+		assertContents(t, &r, "0", "1")
+	})
+	t.Run("ExpireOldest", func(t *testing.T) {
+		r := makeErrorRing(4)
+		for i := 0; i < 6; i++ {
+			r.push(errors.New(strconv.Itoa(i)))
+		}
+		if r.at(0).Error() != "2" || r.at(3).Error() != "5" {
+			t.Fatalf("at should count from the oldest error: %v, %v", r.at(0), r.at(3))
+		}
+
+		r.expireOldest(2)
+		assertContents(t, &r, "4", "5")
+		if r.dropped != 2 || r.expired != 2 || r.total() != 6 {
+			t.Fatalf("ring should count expired errors separately: %d dropped, %d expired, %d total", r.dropped, r.expired, r.total())
+		}
+		if r.cap() != 4 {
+			t.Fatalf("bounded ring should keep its capacity, is %d", r.cap())
+		}
+
+		r.push(errors.New("6"))
+		r.push(errors.New("7"))
+		r.push(errors.New("8"))
+		assertContents(t, &r, "5", "6", "7", "8")
+		if r.dropped != 3 {
+			t.Fatalf("ring should count evicted errors, dropped %d", r.dropped)
+		}
+
+		r.expireOldest(0)
+		assertContents(t, &r, "5", "6", "7", "8")
+		if r.expired != 2 {
+			t.Fatalf("expiring no errors should not expire errors, expired %d", r.expired)
+		}
+
+		r.expireOldest(10)
+		assertContents(t, &r)
+		if r.expired != 6 || r.total() != 9 {
+			t.Fatalf("expiring more errors than the ring holds should empty it: %d expired, %d total", r.expired, r.total())
+		}
+
+		r.reset()
+		if r.expired != 0 || r.total() != 0 {
+			t.Fatalf("reset should clear the counters: %d expired, %d total", r.expired, r.total())
+		}
+	})
+	t.Run("ExpireOldestUnbounded", func(t *testing.T) {
+		r := makeErrorRing(0)
+		for i := 0; i < 4; i++ {
+			r.push(errors.New(strconv.Itoa(i)))
+		}
+
+		r.expireOldest(3)
+		assertContents(t, &r, "3")
+		r.push(errors.New("4"))
+		assertContents(t, &r, "3", "4")
+		if r.dropped != 0 || r.expired != 3 || r.total() != 5 {
+			t.Fatalf("ring should count expired errors: %d dropped, %d expired, %d total", r.dropped, r.expired, r.total())
+		}
+	})
+	t.Run("BoundedPartial", func(t *testing.T) {
+		r := makeErrorRing(4)
+		r.push(errors.New("0"))
+		r.push(errors.New("1"))
+		assertContents(t, &r, "0", "1")
+	})
+	t.Run("BoundedWraps", func(t *testing.T) {
+		r := makeErrorRing(3)
+		for i := 0; i < 7; i++ {
+			r.push(errors.New(strconv.Itoa(i)))
+		}
+		assertContents(t, &r, "4", "5", "6")
+		if r.cap() != 3 {
+			t.Fatalf("capacity should not grow, is %d", r.cap())
+		}
+		if r.dropped != 4 || r.total() != 7 {
+			t.Fatalf("ring should have dropped 4 of 7 errors, dropped=%d total=%d", r.dropped, r.total())
+		}
+	})
+	t.Run("OverwriteReleasesEvicted", func(t *testing.T) {
+		r := makeErrorRing(2)
+		for i := 0; i < 3; i++ {
+			r.push(errors.New(strconv.Itoa(i)))
+		}
+		for _, err := range r.errs[:cap(r.errs)] {
+			if err.Error() == "0" {
+				t.Fatal("evicted error is still reachable from the ring")
+			}
+		}
+	})
+	t.Run("DoesNotAllocateWhenFull", func(t *testing.T) {
+		r := makeErrorRing(16)
+		err := errors.New("err")
+		for i := 0; i < 16; i++ {
+			r.push(err)
+		}
+		if n := testing.AllocsPerRun(1000, func() { r.push(err) }); n != 0 {
+			t.Fatalf("push allocated %f times", n)
+		}
+	})
+}
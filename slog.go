@@ -0,0 +1,116 @@
+//go:build go1.21
+
+package emt
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strconv"
+)
+
+func errorLogAttrs(err error) []slog.Attr {
+	ej := makeErrorJSON(err)
+
+	attrs := make([]slog.Attr, 0, 4)
+	attrs = append(attrs, slog.String("msg", ej.Message), slog.String("type", ej.Type))
+	if ej.Timestamp != nil {
+		attrs = append(attrs, slog.Time("time", *ej.Timestamp))
+	}
+
+	if len(ej.Fields) > 0 {
+		fields := make([]slog.Attr, 0, len(ej.Fields))
+		for key, value := range ej.Fields {
+			fields = append(fields, slog.Any(key, value))
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fields...)})
+	}
+
+	if ej.Caller != nil {
+		attrs = append(attrs, slog.Group("caller",
+			slog.String("function", ej.Caller.Function),
+			slog.String("file", ej.Caller.File),
+			slog.Int("line", ej.Caller.Line),
+		))
+	}
+
+	if len(ej.Causes) > 0 {
+		causes := make([]slog.Attr, len(ej.Causes))
+		for idx, cause := range ej.Causes {
+			causes[idx] = slog.Group(strconv.Itoa(idx), slog.String("msg", cause.Message), slog.String("type", cause.Type))
+		}
+		attrs = append(attrs, slog.Attr{Key: "causes", Value: slog.GroupValue(causes...)})
+	}
+
+	return attrs
+}
+
+// LogValue implements slog.LogValuer, and renders the error as a
+// group with the time the error was collected, the message and type
+// of the annotated error and its cause chain.
+func (e *timestampError) LogValue() slog.Value {
+	if e.err == nil {
+		return slog.GroupValue()
+	}
+
+	return slog.GroupValue(errorLogAttrs(e)...)
+}
+
+// LogValue implements slog.LogValuer, and renders the aggregate as a
+// group with the number of errors and a group for each constituent
+// error, keyed by its index, with the error's message, type,
+// collection time (when known) and cause chain.
+func (e *AggregateError) LogValue() slog.Value {
+	errs := make([]slog.Attr, len(e.errs))
+	for idx, err := range e.errs {
+		errs[idx] = slog.Attr{Key: strconv.Itoa(idx), Value: slog.GroupValue(errorLogAttrs(err)...)}
+	}
+
+	attrs := []slog.Attr{slog.Int("count", len(e.errs))}
+	if e.dropped > 0 {
+		attrs = append(attrs, slog.Int("dropped", e.dropped))
+	}
+	attrs = append(attrs, slog.Attr{Key: "errors", Value: slog.GroupValue(errs...)})
+
+	return slog.GroupValue(attrs...)
+}
+
+// NewSlogCatcher produces a Catcher instance that emits a record to
+// the handler, at the level, for each error that it collects, and
+// aggregates the errors, as NewBasicCatcher, for Resolve. The catcher
+// emits records while it holds its lock, so the records are in the
+// same order as the collected errors. A nil handler is the handler
+// of slog.Default(), a nil level is slog.LevelError, and the records
+// have the time of the clock set with WithClock.
+func NewSlogCatcher(handler slog.Handler, level slog.Leveler, opts ...CatcherOption) Catcher {
+	return MakeSlogCatcher(handler, level, 0, opts...)
+}
+
+// MakeSlogCatcher produces a Catcher instance, as NewSlogCatcher, that
+// never holds more than the specified number of errors when the size
+// is greater than 0. The catcher emits a record for every error,
+// including those it later discards.
+func MakeSlogCatcher(handler slog.Handler, level slog.Leveler, size int, opts ...CatcherOption) Catcher {
+	if handler == nil {
+		handler = slog.Default().Handler()
+	}
+	if level == nil {
+		level = slog.LevelError
+	}
+
+	bc := newBaseCatcher(size, renderBasic, opts)
+	bc.observe = func(err error) {
+		ctx := context.Background()
+		if !handler.Enabled(ctx, level.Level()) {
+			return
+		}
+
+		record := slog.NewRecord(bc.opts.now(), level.Level(), "collected error", 0)
+		record.AddAttrs(slog.Any("err", err))
+
+		_ = handler.Handle(ctx, record)
+	}
+
+	return bc
+}
@@ -0,0 +1,208 @@
+//go:build go1.21
+
+package emt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	versionedCatcherFixtures = append(versionedCatcherFixtures, catcherFixture{
+		Name: "Slog",
+		Factory: func(opts ...CatcherOption) Catcher {
+			return NewSlogCatcher(slog.NewJSONHandler(io.Discard, nil), slog.LevelError, opts...)
+		},
+	})
+}
+
+func TestSlog(t *testing.T) {
+	decode := func(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+		t.Helper()
+		var out []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			rec := map[string]interface{}{}
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, rec)
+		}
+		return out
+	}
+
+	t.Run("TimestampErrorLogValue", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := slog.New(slog.NewJSONHandler(buf, nil))
+
+		ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+		logger.Info("test", "err", &timestampError{err: fmt.Errorf("outer: %w", fs.ErrClosed), time: ts})
+
+		recs := decode(t, buf)
+		group, ok := recs[0]["err"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("error should be a group: %s", buf)
+		}
+		if group["msg"] != "outer: file already closed" || group["type"] != "*fmt.wrapError" {
+			t.Fatalf("unexpected group: %s", buf)
+		}
+		if group["time"] != ts.Format(time.RFC3339) {
+			t.Fatalf("unexpected time: %s", buf)
+		}
+		causes, ok := group["causes"].(map[string]interface{})
+		if !ok || len(causes) != 1 {
+			t.Fatalf("unexpected causes: %s", buf)
+		}
+		if cause := causes["0"].(map[string]interface{}); cause["msg"] != "file already closed" {
+			t.Fatalf("unexpected cause: %s", buf)
+		}
+	})
+	t.Run("FieldsLogValue", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		catcher := NewTimestampCatcher(WithFields("shard", 3))
+		catcher.Add(WrapErrorFields(errors.New("hello"), "request_id", "abc"))
+		slog.New(slog.NewJSONHandler(buf, nil)).Error("failed", "err", catcher.Errors()[0])
+
+		group := decode(t, buf)[0]["err"].(map[string]interface{})
+		fields, ok := group["fields"].(map[string]interface{})
+		if !ok || fields["shard"] != float64(3) || fields["request_id"] != "abc" {
+			t.Fatalf("unexpected fields: %s", buf)
+		}
+		if group["msg"] != "hello" || group["type"] != "*errors.errorString" {
+			t.Fatalf("unexpected group: %s", buf)
+		}
+	})
+	t.Run("Annotations", func(t *testing.T) {
+		for name, opt := range map[string]CatcherOption{
+			"Stack":  WithStackCapture(),
+			"Caller": WithCallerAnnotation(),
+			"Fields": WithFields("shard", 3),
+		} {
+			buf := &bytes.Buffer{}
+			catcher := NewBasicCatcher(opt)
+			catcher.Add(&fs.PathError{Op: "open", Path: "/tmp/foo", Err: fs.ErrNotExist})
+			slog.New(slog.NewJSONHandler(buf, nil)).Error("failed", "err", catcher.Resolve())
+
+			errs := decode(t, buf)[0]["err"].(map[string]interface{})["errors"].(map[string]interface{})
+			group := errs["0"].(map[string]interface{})
+			if group["type"] != "*fs.PathError" {
+				t.Errorf("%s: log should report the collected error: %s", name, buf)
+			}
+			if causes, ok := group["causes"].(map[string]interface{}); !ok || len(causes) != 1 {
+				t.Errorf("%s: causes should not include annotations: %s", name, buf)
+			}
+		}
+	})
+	t.Run("EmptyTimestampError", func(t *testing.T) {
+		if v := (&timestampError{}).LogValue(); len(v.Group()) != 0 {
+			t.Fatalf("empty error should log an empty group: %v", v)
+		}
+	})
+	t.Run("AggregateLogValue", func(t *testing.T) {
+		catcher := MakeTimestampCatcher(2)
+		catcher.New("one")
+		catcher.New("two")
+		catcher.New("three")
+
+		buf := &bytes.Buffer{}
+		slog.New(slog.NewJSONHandler(buf, nil)).Error("failed", "err", catcher.Resolve())
+
+		group := decode(t, buf)[0]["err"].(map[string]interface{})
+		if group["count"] != float64(2) || group["dropped"] != float64(1) {
+			t.Fatalf("unexpected aggregate: %s", buf)
+		}
+		errs := group["errors"].(map[string]interface{})
+		if first := errs["0"].(map[string]interface{}); first["msg"] != "two" || first["time"] == nil {
+			t.Fatalf("unexpected constituent: %s", buf)
+		}
+	})
+	t.Run("Catcher", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		catcher := NewSlogCatcher(slog.NewJSONHandler(buf, nil), slog.LevelWarn)
+
+		catcher.New("one")
+		catcher.Extend([]error{errors.New("two"), nil, errors.New("three")})
+		catcher.Check(func() error { return nil })
+
+		recs := decode(t, buf)
+		if len(recs) != 3 {
+			t.Fatalf("catcher should log three records: %s", buf)
+		}
+		for idx, msg := range []string{"one", "two", "three"} {
+			if recs[idx]["level"] != "WARN" || recs[idx]["err"] != msg {
+				t.Fatalf("unexpected record %d: %v", idx, recs[idx])
+			}
+		}
+
+		if err := catcher.Resolve(); err == nil || err.Error() != "one\ntwo\nthree" {
+			t.Fatalf("catcher should aggregate errors: %v", err)
+		}
+	})
+	t.Run("CatcherDefaults", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+		catcher := NewSlogCatcher(slog.NewJSONHandler(buf, nil), nil, WithClock(NewFakeClock(ts)))
+
+		catcher.New("one")
+
+		recs := decode(t, buf)
+		if len(recs) != 1 || recs[0]["level"] != "ERROR" {
+			t.Fatalf("nil level should log at the error level: %s", buf)
+		}
+		if recs[0]["time"] != ts.Format(time.RFC3339) {
+			t.Fatalf("record should have the time of the clock: %s", buf)
+		}
+	})
+	t.Run("CatcherDefaultHandler", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		defer slog.SetDefault(slog.Default())
+		slog.SetDefault(slog.New(slog.NewJSONHandler(buf, nil)))
+
+		catcher := NewSlogCatcher(nil, nil)
+		catcher.New("one")
+
+		recs := decode(t, buf)
+		if len(recs) != 1 || recs[0]["err"] != "one" {
+			t.Fatalf("nil handler should log to the default logger: %s", buf)
+		}
+	})
+	t.Run("CallerLogValue", func(t *testing.T) {
+		catcher := NewTimestampCatcher(WithCallerAnnotation())
+		catcher.New("hello")
+		caller, ok := ErrorCallerFinder(catcher.Errors()[0])
+		if !ok {
+			t.Fatal("should find caller")
+		}
+
+		buf := &bytes.Buffer{}
+		slog.New(slog.NewJSONHandler(buf, nil)).Error("failed", "err", catcher.Errors()[0])
+		if !strings.Contains(buf.String(), `"caller":{"function":"`+caller.Function+`"`) {
+			t.Fatalf("log should include the caller: %s", buf)
+		}
+	})
+	t.Run("CatcherRespectsLevel", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		handler := slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelError})
+		catcher := MakeSlogCatcher(handler, slog.LevelInfo, 1)
+
+		catcher.New("one")
+		catcher.New("two")
+
+		if buf.Len() != 0 {
+			t.Fatalf("disabled level should not log: %s", buf)
+		}
+		if catcher.Len() != 1 || catcher.Dropped() != 1 {
+			t.Fatal("catcher should respect its size")
+		}
+	})
+}
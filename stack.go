@@ -0,0 +1,170 @@
+package emt
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the maximum number of frames that catchers
+// capture for each error.
+const maxStackDepth = 32
+
+// packageDir is the directory of the source files of this package,
+// which identifies the frames of this package in stack traces.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// isInternalFrame returns true for frames in the (non-test) source
+// files of this package.
+func isInternalFrame(frame runtime.Frame) bool {
+	return filepath.Dir(frame.File) == packageDir && !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// captureStack captures the program counters of the stack of its
+// caller, including the frames of this package, which resolveFrames
+// removes.
+func captureStack() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs)
+	return pcs[:n]
+}
+
+// resolveFrames resolves the program counters into frames, without
+// the frames of this package at the top of the stack.
+func resolveFrames(pcs []uintptr) []runtime.Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	out := make([]runtime.Frame, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if len(out) > 0 || !isInternalFrame(frame) {
+			out = append(out, frame)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return out
+}
+
+// stackError annotates an error with the stack of the code that
+// added it to a catcher. The frames are resolved only when needed.
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+func (e *stackError) Cause() error  { return e.err }
+func (e *stackError) Unwrap() error { return e.err }
+func (e *stackError) Error() string { return e.err.Error() }
+
+// StackTrace returns the frames of the stack of the code that added
+// the error to the catcher, innermost first.
+func (e *stackError) StackTrace() []runtime.Frame { return resolveFrames(e.pcs) }
+
+func (e *stackError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "%+v", e.err)
+			for _, frame := range e.StackTrace() {
+				_, _ = fmt.Fprintf(s, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = fmt.Fprintf(s, "%s", e.err)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.err.Error())
+	}
+}
+
+// ErrorStackFinder finds the stack that a catcher captured for the
+// error, with the WithStackCapture option, unwrapping the error as
+// needed. The frames are innermost first.
+func ErrorStackFinder(err error) ([]runtime.Frame, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *stackError:
+			return e.StackTrace(), true
+		case interface{ Cause() error }:
+			err = e.Cause()
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+// hasStack returns true if the error, or an error that it wraps, has
+// a stack trace: errors from catchers that capture stacks, panics
+// collected by catchers, and errors with a StackTrace method, such as
+// those from github.com/pkg/errors.
+func hasStack(err error) bool {
+	for err != nil {
+		switch err.(type) {
+		case *stackError, *PanicError:
+			return true
+		}
+
+		if reflect.ValueOf(err).MethodByName("StackTrace").IsValid() {
+			return true
+		}
+
+		switch e := err.(type) {
+		case interface{ Cause() error }:
+			err = e.Cause()
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// withStack annotates the error with the stack, unless the error has
+// a stack already.
+func withStack(err error, pcs []uintptr) error {
+	if len(pcs) == 0 || hasStack(err) {
+		return err
+	}
+
+	return annotateInner(err, func(err error) error { return &stackError{err: err, pcs: pcs} })
+}
+
+// annotateInner applies the annotation to the error or, for errors
+// with timestamps, to a copy of the timestamp annotation that wraps
+// the annotated error, so that the timestamp remains the outermost
+// annotation. The groups of deduplicating catchers are not
+// annotated.
+func annotateInner(err error, annotation func(error) error) error {
+	switch e := err.(type) {
+	case *duplicateError:
+		return err
+	case *timestampError:
+		if e.err == nil {
+			return err
+		}
+
+		out := *e
+		out.err = annotation(e.err)
+		return &out
+	default:
+		return annotation(err)
+	}
+}
@@ -0,0 +1,152 @@
+package emt
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type stackTracer struct{ msg string }
+
+func (e *stackTracer) Error() string         { return e.msg }
+func (e *stackTracer) StackTrace() []uintptr { return nil }
+
+func TestStackCapture(t *testing.T) {
+	assertCallerFrame := func(t *testing.T, frames []runtime.Frame, function string) {
+		t.Helper()
+		if len(frames) == 0 {
+			t.Fatal("should capture frames")
+		}
+		if isInternalFrame(frames[0]) {
+			t.Fatalf("first frame should not be internal: %s", frames[0].Function)
+		}
+		if !strings.Contains(frames[0].Function, function) {
+			t.Fatalf("first frame should be the caller, not %s", frames[0].Function)
+		}
+		if !strings.HasSuffix(frames[0].File, "stack_test.go") {
+			t.Fatalf("first frame should be in the test, not %s", frames[0].File)
+		}
+	}
+
+	for _, fix := range catcherFixtures() {
+		t.Run(fix.Name, func(t *testing.T) {
+			t.Run("Disabled", func(t *testing.T) {
+				catcher := fix.Factory()
+				catcher.New("hello")
+				if _, ok := ErrorStackFinder(catcher.Errors()[0]); ok {
+					t.Fatal("catchers should not capture stacks by default")
+				}
+			})
+			t.Run("Add", func(t *testing.T) {
+				catcher := fix.Factory(WithStackCapture())
+				catcher.Add(errors.New("hello"))
+
+				frames, ok := ErrorStackFinder(catcher.Errors()[0])
+				if !ok {
+					t.Fatal("should find stack")
+				}
+				assertCallerFrame(t, frames, "TestStackCapture")
+			})
+			t.Run("Wrappers", func(t *testing.T) {
+				catcher := fix.Factory(WithStackCapture())
+				catcher.AddWhen(true, errors.New("one"))
+				catcher.Errorf("two %d", 2)
+				catcher.Check(func() error { return errors.New("three") })
+				catcher.Extend([]error{errors.New("four")})
+
+				for _, err := range catcher.Errors() {
+					frames, ok := ErrorStackFinder(err)
+					if !ok {
+						t.Fatalf("should find stack for %v", err)
+					}
+					assertCallerFrame(t, frames, "TestStackCapture")
+				}
+			})
+			t.Run("ExistingStack", func(t *testing.T) {
+				catcher := fix.Factory(WithStackCapture())
+				catcher.Add(fmt.Errorf("wrapped: %w", &stackTracer{msg: "hello"}))
+				catcher.Add(&PanicError{Value: "boom"})
+
+				for _, err := range catcher.Errors() {
+					if _, ok := ErrorStackFinder(err); ok {
+						t.Fatalf("should not capture stacks for errors with stacks: %v", err)
+					}
+				}
+			})
+			t.Run("Message", func(t *testing.T) {
+				plain := fix.Factory()
+				plain.New("hello")
+
+				catcher := fix.Factory(WithStackCapture())
+				catcher.New("hello")
+
+				if plain.String() != catcher.String() && !strings.Contains(catcher.String(), "stack_test.go") {
+					t.Fatalf("stack capture should only change extended output: %q", catcher)
+				}
+				if err := catcher.Errors()[0]; !strings.Contains(err.Error(), "hello") {
+					t.Fatalf("unexpected message %q", err)
+				}
+			})
+			t.Run("Format", func(t *testing.T) {
+				if fix.Name == "Dedup" {
+					t.Skip("deduplicated errors render their counts rather than details")
+				}
+
+				catcher := fix.Factory(WithStackCapture())
+				catcher.New("hello")
+
+				out := fmt.Sprintf("%+v", catcher.Resolve())
+				if !strings.Contains(out, "TestStackCapture") || !strings.Contains(out, "stack_test.go:") {
+					t.Fatalf("%%+v should render the stack: %s", out)
+				}
+				if out := fmt.Sprintf("%v", catcher.Resolve()); out != catcher.String() {
+					t.Fatalf("%%v should render as the catcher does: %s", out)
+				}
+			})
+		})
+	}
+	t.Run("ExtendedCatcherRendersStacks", func(t *testing.T) {
+		catcher := NewExtendedCatcher(WithStackCapture())
+		catcher.New("hello")
+		if !strings.Contains(catcher.String(), "stack_test.go:") {
+			t.Fatalf("extended catcher should render the stack: %s", catcher)
+		}
+
+		catcher = NewBasicCatcher(WithStackCapture())
+		catcher.New("hello")
+		if catcher.String() != "hello" {
+			t.Fatalf("basic catcher should not render the stack: %s", catcher)
+		}
+	})
+	t.Run("KeepsTimestamp", func(t *testing.T) {
+		orig := WrapErrorTime(errors.New("hello"))
+		ts, _ := ErrorTimeFinder(orig)
+
+		catcher := NewBasicCatcher(WithStackCapture())
+		catcher.Add(orig)
+
+		err := catcher.Errors()[0]
+		if found, ok := ErrorTimeFinder(err); !ok || !found.Equal(ts) {
+			t.Fatal("stack capture should retain the timestamp")
+		}
+		if _, ok := err.(*timestampError); !ok {
+			t.Fatalf("timestamp should be the outermost annotation, not %T", err)
+		}
+		if _, ok := ErrorStackFinder(err); !ok {
+			t.Fatal("should find stack inside timestamp")
+		}
+	})
+	t.Run("VerbsWithoutPlus", func(t *testing.T) {
+		err := &stackError{err: errors.New("hello"), pcs: captureStack()}
+		for verb, expected := range map[string]string{"%s": "hello", "%v": "hello", "%q": `"hello"`} {
+			if out := fmt.Sprintf(verb, err); out != expected {
+				t.Errorf("%s rendered %q, expected %q", verb, out, expected)
+			}
+		}
+		if !errors.Is(err, err.err) {
+			t.Error("stack annotation should wrap the error")
+		}
+	})
+}